@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/internal/bulk"
+	"github.com/tech-arch1tect/lssh/internal/config"
+	"github.com/tech-arch1tect/lssh/internal/provider"
+	"github.com/tech-arch1tect/lssh/internal/ssh"
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+func runExec(args []string) error {
+	cfg, providers, err := loadProviders()
+	if err != nil {
+		return err
+	}
+
+	defaults := cfg.GetBulkOptions()
+
+	flags := flag.NewFlagSet("exec", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: lssh exec [flags] <command>")
+		flags.PrintDefaults()
+	}
+	groups := flags.String("group", "", "Comma-separated group patterns to run on")
+	hosts := flags.String("host", "", "Comma-separated host name patterns to run on")
+	all := flags.Bool("all", false, "Run on every host")
+	username := flags.String("user", "", "Override the username")
+	parallel := flags.Int("parallel", defaults.Parallel, "Maximum number of hosts running at once (0 for unlimited)")
+	batch := flags.String("batch", defaults.BatchString(), "Batch size as a host count or percentage (e.g. 10%)")
+	pause := flags.Duration("pause", defaults.Pause, "Pause between batches")
+	maxFailures := flags.Int("max-failures", defaults.MaxFailures, "Abort after this many failed hosts (0 to never abort)")
+	timeout := flags.Duration("timeout", defaults.Timeout, "Per-host command timeout (0 for none)")
+	yes := flags.Bool("yes", false, "Do not ask for confirmation")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	command := strings.Join(flags.Args(), " ")
+	if command == "" {
+		flags.Usage()
+		return fmt.Errorf("no command given")
+	}
+	if *groups == "" && *hosts == "" && !*all {
+		return fmt.Errorf("select hosts with -group, -host or -all")
+	}
+
+	options := bulk.Options{
+		Parallel:    *parallel,
+		Pause:       *pause,
+		MaxFailures: *maxFailures,
+		Timeout:     *timeout,
+	}
+	options.BatchSize, options.BatchPercent, err = bulk.ParseBatch(*batch)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	selected, err := selectExecHosts(ctx, cfg, providers, splitPatterns(*groups), splitPatterns(*hosts), *all)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no hosts match the selection")
+	}
+
+	names := make([]string, len(selected))
+	for i, host := range selected {
+		names[i] = host.Name
+	}
+	fmt.Printf("Command: %s\n", command)
+	fmt.Printf("Hosts (%d): %s\n", len(selected), strings.Join(names, ", "))
+	fmt.Printf("Plan: %s\n", options.Describe(len(selected)))
+
+	if !*yes {
+		fmt.Print("Continue? [y/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	executor, err := ssh.NewExecutor(cfg.GetExecutor())
+	if err != nil {
+		return err
+	}
+	defer executor.Close()
+
+	var mu sync.Mutex
+	results := make(map[*types.Host]bulk.Event)
+	summary := bulk.Execute(ctx, selected, options, func(ctx context.Context, host *types.Host) bulk.Result {
+		stdout := &prefixWriter{mu: &mu, out: os.Stdout, prefix: host.Name + " | "}
+		stderr := &prefixWriter{mu: &mu, out: os.Stderr, prefix: host.Name + " ! "}
+
+		result := bulk.Run(ctx, executor, host, *username, command, options.Timeout, stdout, stderr)
+		stdout.Flush()
+		stderr.Flush()
+		return result
+	}, func(event bulk.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case event.Host == nil && !event.Resume.IsZero():
+			fmt.Printf("--- pausing until %s before batch %d/%d\n", event.Resume.Format("15:04:05"), event.Batch, event.Batches)
+		case event.Host == nil:
+			if event.Batches > 1 {
+				fmt.Printf("--- batch %d/%d\n", event.Batch, event.Batches)
+			}
+		case event.Status.Finished():
+			results[event.Host] = event
+			if event.Result.Err != nil {
+				fmt.Printf("%s: %s: %v\n", event.Host.Name, event.Status, event.Result.Err)
+			} else {
+				fmt.Printf("%s: %s\n", event.Host.Name, event.Status)
+			}
+		}
+	})
+
+	fmt.Println()
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "HOST\tSTATUS\tEXIT\tSIGNAL\tDURATION")
+	for _, host := range selected {
+		event := results[host]
+		exitCode := ""
+		duration := ""
+		if event.Status != bulk.StatusSkipped {
+			if event.Status != bulk.StatusTimedOut && event.Result.ExitCode >= 0 {
+				exitCode = strconv.Itoa(event.Result.ExitCode)
+			}
+			duration = event.Result.Duration.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", host.Name, event.Status, exitCode, event.Result.Signal, duration)
+	}
+	table.Flush()
+
+	fmt.Printf("\n%d ok, %d failed, %d timed out, %d skipped\n", summary.Succeeded, summary.Failed, summary.TimedOut, summary.Skipped)
+	if summary.Aborted {
+		return fmt.Errorf("aborted: %d hosts failed, the failure threshold was reached", summary.Failed+summary.TimedOut)
+	}
+	if summary.Failed > 0 || summary.TimedOut > 0 || summary.Skipped > 0 {
+		return fmt.Errorf("command did not succeed on every host")
+	}
+	return nil
+}
+
+func selectExecHosts(ctx context.Context, cfg *config.Config, providers []provider.Provider, groupPatterns, hostPatterns []string, all bool) ([]*types.Host, error) {
+	var candidates []*types.Host
+	excluded := make(map[string]bool)
+
+	for _, p := range providers {
+		groups, err := p.GetGroups(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load data from %s: %w", p.Name(), err)
+		}
+
+		cfg.ApplyGroupTransports(groups)
+
+		for _, group := range groups {
+			if cfg.IsGroupExcluded(group.Name, config.HardExclude) {
+				for _, host := range group.AllHosts() {
+					excluded[host.Key()] = true
+				}
+				continue
+			}
+			if cfg.IsGroupExcluded(group.Name, config.SoftExclude) {
+				continue
+			}
+			candidates = append(candidates, matchGroupHosts(group, groupPatterns, all)...)
+		}
+	}
+
+	var selected []*types.Host
+	seen := make(map[string]bool)
+	for _, host := range candidates {
+		key := host.Key()
+		if excluded[key] || seen[key] || cfg.IsHostExcluded(host.Name) {
+			continue
+		}
+		if len(hostPatterns) > 0 && !matchesAnyPattern(host.Name, hostPatterns) {
+			continue
+		}
+		seen[key] = true
+		selected = append(selected, host)
+	}
+
+	return selected, nil
+}
+
+func matchGroupHosts(group *types.Group, patterns []string, all bool) []*types.Host {
+	if all || len(patterns) == 0 || matchesAnyPattern(group.Name, patterns) {
+		return group.AllHosts()
+	}
+
+	var hosts []*types.Host
+	for _, subGroup := range group.SubGroups {
+		hosts = append(hosts, matchGroupHosts(subGroup, patterns, all)...)
+	}
+	return hosts
+}
+
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if config.MatchesPattern(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+type prefixWriter struct {
+	mu      *sync.Mutex
+	out     io.Writer
+	prefix  string
+	pending []byte
+}
+
+func (w *prefixWriter) Write(data []byte) (int, error) {
+	w.pending = append(w.pending, data...)
+
+	for {
+		index := bytes.IndexByte(w.pending, '\n')
+		if index < 0 {
+			break
+		}
+		w.writeLine(w.pending[:index])
+		w.pending = w.pending[index+1:]
+	}
+
+	return len(data), nil
+}
+
+func (w *prefixWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.writeLine(w.pending)
+		w.pending = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	fmt.Fprintf(w.out, "%s%s\n", w.prefix, bytes.TrimRight(line, "\r"))
+}
@@ -0,0 +1,308 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/internal/ssh"
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type Options struct {
+	Parallel     int
+	BatchSize    int
+	BatchPercent int
+	Pause        time.Duration
+	MaxFailures  int
+	Timeout      time.Duration
+}
+
+type Status int
+
+const (
+	StatusPending Status = iota
+	StatusRunning
+	StatusSucceeded
+	StatusFailed
+	StatusTimedOut
+	StatusSkipped
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusRunning:
+		return "running"
+	case StatusSucceeded:
+		return "ok"
+	case StatusFailed:
+		return "failed"
+	case StatusTimedOut:
+		return "timeout"
+	case StatusSkipped:
+		return "skipped"
+	default:
+		return "pending"
+	}
+}
+
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusTimedOut || s == StatusSkipped
+}
+
+type Result struct {
+	ExitCode int
+	Signal   string
+	Duration time.Duration
+	TimedOut bool
+	Err      error
+}
+
+func (r Result) Status() Status {
+	switch {
+	case r.TimedOut:
+		return StatusTimedOut
+	case r.Err != nil:
+		return StatusFailed
+	default:
+		return StatusSucceeded
+	}
+}
+
+type Event struct {
+	Host    *types.Host
+	Status  Status
+	Result  Result
+	Batch   int
+	Batches int
+	Resume  time.Time
+}
+
+type Summary struct {
+	Succeeded int
+	Failed    int
+	TimedOut  int
+	Skipped   int
+	Aborted   bool
+}
+
+type RunFunc func(ctx context.Context, host *types.Host) Result
+
+func Run(ctx context.Context, executor ssh.Executor, host *types.Host, username, command string, timeout time.Duration, stdout, stderr io.Writer) Result {
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	execResult, err := executor.Run(runCtx, host, username, command, stdout, stderr)
+	result := Result{
+		ExitCode: execResult.ExitCode,
+		Signal:   execResult.Signal,
+		Duration: time.Since(started),
+		Err:      err,
+	}
+
+	if err != nil && ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		result.TimedOut = true
+		result.Err = fmt.Errorf("timed out after %s", timeout)
+	} else if err == nil {
+		result.Err = execResult.Err()
+	}
+
+	return result
+}
+
+func ParseBatch(value string) (size, percent int, err error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, 0, nil
+	}
+
+	if number, ok := strings.CutSuffix(value, "%"); ok {
+		percent, err = strconv.Atoi(strings.TrimSpace(number))
+		if err != nil || percent < 1 || percent > 100 {
+			return 0, 0, fmt.Errorf("invalid batch percentage %q (expected 1%%-100%%)", value)
+		}
+		return 0, percent, nil
+	}
+
+	size, err = strconv.Atoi(value)
+	if err != nil || size < 0 {
+		return 0, 0, fmt.Errorf("invalid batch size %q (expected a host count or a percentage)", value)
+	}
+	return size, 0, nil
+}
+
+func (o Options) BatchString() string {
+	switch {
+	case o.BatchPercent > 0:
+		return fmt.Sprintf("%d%%", o.BatchPercent)
+	case o.BatchSize > 0:
+		return strconv.Itoa(o.BatchSize)
+	default:
+		return ""
+	}
+}
+
+func (o Options) Describe(hostCount int) string {
+	var parts []string
+
+	if o.Parallel > 0 {
+		parts = append(parts, fmt.Sprintf("at most %d at a time", o.Parallel))
+	} else {
+		parts = append(parts, "all at once")
+	}
+
+	if batchSize := o.batchSize(hostCount); batchSize < hostCount {
+		batches := (hostCount + batchSize - 1) / batchSize
+		batch := fmt.Sprintf("%d batches of %d", batches, batchSize)
+		if o.Pause > 0 {
+			batch += fmt.Sprintf(" with a %s pause", o.Pause)
+		}
+		parts = append(parts, batch)
+	}
+
+	if o.Timeout > 0 {
+		parts = append(parts, fmt.Sprintf("%s timeout per host", o.Timeout))
+	} else {
+		parts = append(parts, "no timeout")
+	}
+
+	if o.MaxFailures > 0 {
+		if o.MaxFailures == 1 {
+			parts = append(parts, "abort after the first failure")
+		} else {
+			parts = append(parts, fmt.Sprintf("abort after %d failures", o.MaxFailures))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (o Options) Batches(hosts []*types.Host) [][]*types.Host {
+	batchSize := o.batchSize(len(hosts))
+
+	var batches [][]*types.Host
+	for start := 0; start < len(hosts); start += batchSize {
+		end := start + batchSize
+		if end > len(hosts) {
+			end = len(hosts)
+		}
+		batches = append(batches, hosts[start:end])
+	}
+	return batches
+}
+
+func (o Options) batchSize(hostCount int) int {
+	size := hostCount
+	if o.BatchPercent > 0 {
+		size = (hostCount*o.BatchPercent + 99) / 100
+	} else if o.BatchSize > 0 {
+		size = o.BatchSize
+	}
+
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+func Execute(ctx context.Context, hosts []*types.Host, options Options, run RunFunc, notify func(Event)) Summary {
+	if notify == nil {
+		notify = func(Event) {}
+	}
+
+	var mu sync.Mutex
+	var summary Summary
+
+	skip := func(host *types.Host) {
+		mu.Lock()
+		summary.Skipped++
+		mu.Unlock()
+		notify(Event{Host: host, Status: StatusSkipped})
+	}
+
+	stopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return summary.Aborted || ctx.Err() != nil
+	}
+
+	batches := options.Batches(hosts)
+	for index, batch := range batches {
+		if index > 0 && options.Pause > 0 && !stopped() {
+			notify(Event{Batch: index + 1, Batches: len(batches), Resume: time.Now().Add(options.Pause)})
+			select {
+			case <-time.After(options.Pause):
+			case <-ctx.Done():
+			}
+		}
+
+		if !stopped() {
+			notify(Event{Batch: index + 1, Batches: len(batches)})
+		}
+
+		parallel := options.Parallel
+		if parallel <= 0 || parallel > len(batch) {
+			parallel = len(batch)
+		}
+		slots := make(chan struct{}, parallel)
+
+		var wg sync.WaitGroup
+		for _, host := range batch {
+			if stopped() {
+				skip(host)
+				continue
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				skip(host)
+				continue
+			}
+			if stopped() {
+				<-slots
+				skip(host)
+				continue
+			}
+
+			wg.Add(1)
+			notify(Event{Host: host, Status: StatusRunning})
+			go func(host *types.Host) {
+				defer wg.Done()
+
+				result := run(ctx, host)
+				status := result.Status()
+
+				mu.Lock()
+				switch status {
+				case StatusSucceeded:
+					summary.Succeeded++
+				case StatusTimedOut:
+					summary.TimedOut++
+				default:
+					summary.Failed++
+				}
+				if options.MaxFailures > 0 && summary.Failed+summary.TimedOut >= options.MaxFailures {
+					summary.Aborted = true
+				}
+				mu.Unlock()
+
+				notify(Event{Host: host, Status: status, Result: result})
+				<-slots
+			}(host)
+		}
+		wg.Wait()
+	}
+
+	return summary
+}
@@ -0,0 +1,311 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+func testHosts(count int) []*types.Host {
+	hosts := make([]*types.Host, count)
+	for i := range hosts {
+		hosts[i] = &types.Host{Name: fmt.Sprintf("host%d", i), Hostname: fmt.Sprintf("10.0.0.%d", i)}
+	}
+	return hosts
+}
+
+func TestParseBatch(t *testing.T) {
+	tests := []struct {
+		value   string
+		size    int
+		percent int
+		wantErr bool
+	}{
+		{"", 0, 0, false},
+		{" 5 ", 5, 0, false},
+		{"0", 0, 0, false},
+		{"10%", 0, 10, false},
+		{" 25 % ", 0, 25, false},
+		{"100%", 0, 100, false},
+		{"0%", 0, 0, true},
+		{"101%", 0, 0, true},
+		{"-1", 0, 0, true},
+		{"abc", 0, 0, true},
+		{"%", 0, 0, true},
+	}
+
+	for _, test := range tests {
+		size, percent, err := ParseBatch(test.value)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseBatch(%q) error = %v, wantErr %v", test.value, err, test.wantErr)
+			continue
+		}
+		if size != test.size || percent != test.percent {
+			t.Errorf("ParseBatch(%q) = %d, %d%%, want %d, %d%%", test.value, size, percent, test.size, test.percent)
+		}
+	}
+}
+
+func TestBatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		hosts   int
+		options Options
+		want    []int
+	}{
+		{"no batching", 5, Options{}, []int{5}},
+		{"fixed size", 5, Options{BatchSize: 2}, []int{2, 2, 1}},
+		{"size larger than hosts", 3, Options{BatchSize: 10}, []int{3}},
+		{"percentage rounds up", 10, Options{BatchPercent: 25}, []int{3, 3, 3, 1}},
+		{"exact percentage", 10, Options{BatchPercent: 50}, []int{5, 5}},
+		{"small percentage is at least one host", 10, Options{BatchPercent: 1}, []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		{"third of three hosts", 3, Options{BatchPercent: 33}, []int{1, 1, 1}},
+		{"full percentage", 7, Options{BatchPercent: 100}, []int{7}},
+		{"percentage wins over size", 4, Options{BatchSize: 1, BatchPercent: 50}, []int{2, 2}},
+		{"no hosts", 0, Options{BatchSize: 2}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hosts := testHosts(test.hosts)
+
+			var sizes []int
+			var flattened []*types.Host
+			for _, batch := range test.options.Batches(hosts) {
+				sizes = append(sizes, len(batch))
+				flattened = append(flattened, batch...)
+			}
+
+			if !reflect.DeepEqual(sizes, test.want) {
+				t.Errorf("batch sizes = %v, want %v", sizes, test.want)
+			}
+			if len(hosts) > 0 && !reflect.DeepEqual(flattened, hosts) {
+				t.Errorf("batches do not preserve host order")
+			}
+		})
+	}
+}
+
+type recorder struct {
+	mu       sync.Mutex
+	order    []string
+	finished map[string]Status
+}
+
+func newRecorder() *recorder {
+	return &recorder{finished: make(map[string]Status)}
+}
+
+func (r *recorder) notify(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case event.Host != nil && event.Status == StatusRunning:
+		r.order = append(r.order, event.Host.Name)
+	case event.Host != nil && event.Status.Finished():
+		if _, exists := r.finished[event.Host.Name]; exists {
+			panic("host finished twice: " + event.Host.Name)
+		}
+		r.finished[event.Host.Name] = event.Status
+	}
+}
+
+func failing(names ...string) RunFunc {
+	failed := make(map[string]bool)
+	for _, name := range names {
+		failed[name] = true
+	}
+	return func(ctx context.Context, host *types.Host) Result {
+		if failed[host.Name] {
+			return Result{ExitCode: 1, Err: errors.New("exit status 1")}
+		}
+		return Result{}
+	}
+}
+
+func TestExecuteFailureThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		hosts   int
+		options Options
+		run     RunFunc
+		want    Summary
+		ran     []string
+	}{
+		{
+			name:    "no threshold runs every host",
+			hosts:   4,
+			options: Options{Parallel: 1, BatchSize: 2},
+			run:     failing("host0", "host1", "host2"),
+			want:    Summary{Succeeded: 1, Failed: 3},
+			ran:     []string{"host0", "host1", "host2", "host3"},
+		},
+		{
+			name:    "threshold reached in the first batch skips the next batches",
+			hosts:   6,
+			options: Options{Parallel: 2, BatchSize: 2, MaxFailures: 2},
+			run:     failing("host0", "host1"),
+			want:    Summary{Failed: 2, Skipped: 4, Aborted: true},
+			ran:     []string{"host0", "host1"},
+		},
+		{
+			name:    "failures add up across batches",
+			hosts:   6,
+			options: Options{Parallel: 1, BatchSize: 2, MaxFailures: 2},
+			run:     failing("host1", "host2"),
+			want:    Summary{Succeeded: 1, Failed: 2, Skipped: 3, Aborted: true},
+			ran:     []string{"host0", "host1", "host2"},
+		},
+		{
+			name:    "threshold not reached",
+			hosts:   4,
+			options: Options{Parallel: 1, BatchSize: 2, MaxFailures: 2},
+			run:     failing("host3"),
+			want:    Summary{Succeeded: 3, Failed: 1},
+			ran:     []string{"host0", "host1", "host2", "host3"},
+		},
+		{
+			name:    "timeouts count as failures",
+			hosts:   3,
+			options: Options{Parallel: 1, MaxFailures: 1},
+			run: func(ctx context.Context, host *types.Host) Result {
+				return Result{ExitCode: -1, TimedOut: true, Err: errors.New("timed out")}
+			},
+			want: Summary{TimedOut: 1, Skipped: 2, Aborted: true},
+			ran:  []string{"host0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hosts := testHosts(test.hosts)
+			events := newRecorder()
+
+			summary := Execute(context.Background(), hosts, test.options, test.run, events.notify)
+
+			if summary != test.want {
+				t.Errorf("summary = %+v, want %+v", summary, test.want)
+			}
+			if !reflect.DeepEqual(events.order, test.ran) {
+				t.Errorf("ran %v, want %v", events.order, test.ran)
+			}
+			if len(events.finished) != len(hosts) {
+				t.Errorf("%d hosts finished, want %d", len(events.finished), len(hosts))
+			}
+		})
+	}
+}
+
+func TestExecuteParallelLimit(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+
+	run := func(ctx context.Context, host *types.Host) Result {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return Result{}
+	}
+
+	summary := Execute(context.Background(), testHosts(12), Options{Parallel: 3, BatchSize: 5}, run, nil)
+	if summary.Succeeded != 12 {
+		t.Errorf("summary = %+v, want 12 succeeded", summary)
+	}
+	if peak != 3 {
+		t.Errorf("peak concurrency = %d, want 3", peak)
+	}
+}
+
+func TestExecuteCancellation(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		want    Summary
+	}{
+		{"within a batch", Options{Parallel: 1}, Summary{Succeeded: 1, Failed: 1, Skipped: 2}},
+		{"between batches", Options{Parallel: 1, BatchSize: 1}, Summary{Succeeded: 1, Failed: 1, Skipped: 2}},
+		{"during a pause", Options{Parallel: 1, BatchSize: 1, Pause: time.Hour}, Summary{Succeeded: 1, Skipped: 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			hosts := testHosts(4)
+			events := newRecorder()
+			run := func(ctx context.Context, host *types.Host) Result {
+				if host.Name == "host1" {
+					cancel()
+					return Result{ExitCode: -1, Err: ctx.Err()}
+				}
+				return Result{}
+			}
+
+			notify := func(event Event) {
+				if !event.Resume.IsZero() {
+					cancel()
+				}
+				events.notify(event)
+			}
+
+			done := make(chan Summary)
+			go func() {
+				done <- Execute(ctx, hosts, test.options, run, notify)
+			}()
+
+			var summary Summary
+			select {
+			case summary = <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Execute did not return after cancellation")
+			}
+
+			if summary != test.want {
+				t.Errorf("summary = %+v, want %+v", summary, test.want)
+			}
+			if len(events.finished) != len(hosts) {
+				t.Errorf("%d hosts finished, want %d", len(events.finished), len(hosts))
+			}
+			for _, name := range []string{"host2", "host3"} {
+				if events.finished[name] != StatusSkipped {
+					t.Errorf("%s status = %s, want skipped", name, events.finished[name])
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteCancelledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	summary := Execute(ctx, testHosts(3), Options{BatchSize: 2}, func(ctx context.Context, host *types.Host) Result {
+		called = true
+		return Result{}
+	}, nil)
+
+	if called {
+		t.Error("run was called after cancellation")
+	}
+	if want := (Summary{Skipped: 3}); summary != want {
+		t.Errorf("summary = %+v, want %+v", summary, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -23,14 +24,18 @@ type CachedProvider struct {
 	cacheDir        string
 	ttl             time.Duration
 	useExpiredCache bool
+	offline         bool
+	warnings        []string
 }
 
 type cacheEntry struct {
 	Groups    []*types.Group `json:"groups"`
 	Timestamp time.Time      `json:"timestamp"`
+	Diff      *InventoryDiff `json:"diff,omitempty"`
+	Version   string         `json:"version,omitempty"`
 }
 
-func NewCachedProvider(p provider.Provider, providerType, filePath string) *CachedProvider {
+func NewCachedProvider(p provider.Provider, providerType, filePath string, offline bool) *CachedProvider {
 	cacheDir := getCacheDir()
 	ttl := getCacheTTL()
 
@@ -41,6 +46,7 @@ func NewCachedProvider(p provider.Provider, providerType, filePath string) *Cach
 		cacheDir:        cacheDir,
 		ttl:             ttl,
 		useExpiredCache: false,
+		offline:         offline,
 	}
 }
 
@@ -52,15 +58,42 @@ func (cp *CachedProvider) GetGroups(ctx context.Context) ([]*types.Group, error)
 	cacheKey := cp.getCacheKey()
 	cacheFile := filepath.Join(cp.cacheDir, cacheKey+".json")
 
-	if entry, err := cp.loadFromCache(cacheFile); err == nil {
-		if time.Since(entry.Timestamp) < cp.ttl || cp.useExpiredCache {
-			return entry.Groups, nil
+	cp.warnings = nil
+
+	entry, err := cp.loadFromCache(cacheFile)
+	if cp.offline {
+		if err != nil {
+			return nil, fmt.Errorf("no cached data available for %s in offline mode", cp.provider.Name())
 		}
+		return entry.Groups, nil
 	}
 
-	groups, err := cp.provider.GetGroups(ctx)
-	if err != nil {
-		return nil, err
+	if err == nil && (time.Since(entry.Timestamp) < cp.ttl || cp.useExpiredCache) {
+		return entry.Groups, nil
+	}
+
+	var version string
+	var groups []*types.Group
+	if versioned, ok := cp.provider.(provider.VersionedProvider); ok {
+		since := ""
+		if entry != nil {
+			since = entry.Version
+		}
+
+		version, _ = versioned.Version(ctx, since)
+		if entry != nil && version != "" && version == entry.Version {
+			groups = entry.Groups
+		}
+	}
+
+	if groups == nil {
+		groups, err = cp.provider.GetGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if wp, ok := cp.provider.(provider.WarningProvider); ok {
+			cp.warnings = wp.Warnings()
+		}
 	}
 
 	totalHosts := 0
@@ -69,12 +102,44 @@ func (cp *CachedProvider) GetGroups(ctx context.Context) ([]*types.Group, error)
 	}
 
 	if totalHosts > 0 {
-		cp.saveToCache(cacheFile, groups)
+		var diff *InventoryDiff
+		if entry != nil {
+			diff = ComputeDiff(cp.provider.Name(), entry.Timestamp, entry.Groups, time.Now(), groups)
+		}
+		cp.saveToCache(cacheFile, groups, diff, version)
 	}
 
 	return groups, nil
 }
 
+func (cp *CachedProvider) Unwrap() provider.Provider {
+	return cp.provider
+}
+
+func (cp *CachedProvider) Warnings() []string {
+	return cp.warnings
+}
+
+func (cp *CachedProvider) IsOffline() bool {
+	return cp.offline
+}
+
+func (cp *CachedProvider) CachedAt() (time.Time, bool) {
+	entry, err := cp.loadFromCache(filepath.Join(cp.cacheDir, cp.getCacheKey()+".json"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return entry.Timestamp, true
+}
+
+func (cp *CachedProvider) LastDiff() (*InventoryDiff, bool) {
+	entry, err := cp.loadFromCache(filepath.Join(cp.cacheDir, cp.getCacheKey()+".json"))
+	if err != nil || entry.Diff == nil {
+		return nil, false
+	}
+	return entry.Diff, true
+}
+
 func (cp *CachedProvider) getCacheKey() string {
 	keyData := fmt.Sprintf("%s:%s:%s", cp.providerType, cp.filePath, cp.provider.Name())
 	h := sha256.New()
@@ -96,7 +161,7 @@ func (cp *CachedProvider) loadFromCache(cacheFile string) (*cacheEntry, error) {
 	return &entry, nil
 }
 
-func (cp *CachedProvider) saveToCache(cacheFile string, groups []*types.Group) {
+func (cp *CachedProvider) saveToCache(cacheFile string, groups []*types.Group, diff *InventoryDiff, version string) {
 	if err := os.MkdirAll(cp.cacheDir, 0755); err != nil {
 		return
 	}
@@ -104,6 +169,8 @@ func (cp *CachedProvider) saveToCache(cacheFile string, groups []*types.Group) {
 	entry := cacheEntry{
 		Groups:    groups,
 		Timestamp: time.Now(),
+		Diff:      diff,
+		Version:   version,
 	}
 
 	data, err := json.MarshalIndent(entry, "", "  ")
@@ -177,13 +244,24 @@ func ClearCache() error {
 }
 
 func CheckExpiredCaches(providers []provider.Provider) error {
-	cacheDir := getCacheDir()
+	return checkExpiredCaches(providers, os.Stdin)
+}
+
+func checkExpiredCaches(providers []provider.Provider, input io.Reader) error {
 	ttl := getCacheTTL()
+	reader := bufio.NewReader(input)
 
 	for _, p := range providers {
 		if cp, ok := p.(*CachedProvider); ok {
+			if cp.offline {
+				continue
+			}
+			if _, versioned := cp.provider.(provider.VersionedProvider); versioned {
+				continue
+			}
+
 			cacheKey := cp.getCacheKey()
-			cacheFile := filepath.Join(cacheDir, cacheKey+".json")
+			cacheFile := filepath.Join(cp.cacheDir, cacheKey+".json")
 
 			if entry, err := cp.loadFromCache(cacheFile); err == nil {
 				if time.Since(entry.Timestamp) >= ttl {
@@ -191,20 +269,13 @@ func CheckExpiredCaches(providers []provider.Provider) error {
 					fmt.Printf("Cache for %s expired %v ago.\n", cp.provider.Name(), age.Round(time.Minute))
 					fmt.Print("Use expired cache? [y/N]: ")
 
-					reader := bufio.NewReader(os.Stdin)
 					response, err := reader.ReadString('\n')
-					if err != nil {
+					if err != nil && response == "" {
 						continue
 					}
 
 					response = strings.TrimSpace(strings.ToLower(response))
-					if response == "y" || response == "yes" {
-						cp.useExpiredCache = true
-					} else {
-						if err := os.Remove(cacheFile); err != nil {
-							fmt.Printf("Warning: Could not remove expired cache: %v\n", err)
-						}
-					}
+					cp.useExpiredCache = response == "y" || response == "yes"
 				}
 			}
 		}
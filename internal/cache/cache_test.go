@@ -0,0 +1,258 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/provider"
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type stubProvider struct {
+	groups  []*types.Group
+	fetches int
+}
+
+func (s *stubProvider) Name() string {
+	return "stub"
+}
+
+func (s *stubProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	s.fetches++
+	return s.groups, nil
+}
+
+type versionedStub struct {
+	stubProvider
+	version  string
+	versions int
+	since    []string
+}
+
+func newVersionedStub(version string, groups []*types.Group) *versionedStub {
+	return &versionedStub{stubProvider: stubProvider{groups: groups}, version: version}
+}
+
+func (s *versionedStub) Version(ctx context.Context, since string) (string, error) {
+	s.versions++
+	s.since = append(s.since, since)
+	return s.version, nil
+}
+
+func stubGroups(names ...string) []*types.Group {
+	group := &types.Group{Name: "all"}
+	for _, name := range names {
+		group.Hosts = append(group.Hosts, &types.Host{Name: name, Hostname: name + ".example.com"})
+	}
+	return []*types.Group{group}
+}
+
+func newTestCachedProvider(t *testing.T, stub provider.Provider) *CachedProvider {
+	t.Helper()
+	t.Setenv("LSSH_CACHE_DIR", t.TempDir())
+	t.Setenv("LSSH_CACHE_TTL", "1h")
+	return NewCachedProvider(stub, "stub", "stub", false)
+}
+
+func loadEntry(t *testing.T, cp *CachedProvider) *cacheEntry {
+	t.Helper()
+
+	entry, err := cp.loadFromCache(filepath.Join(cp.cacheDir, cp.getCacheKey()+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func expireEntry(t *testing.T, cp *CachedProvider) {
+	t.Helper()
+
+	entry := loadEntry(t, cp)
+	entry.Timestamp = time.Now().Add(-2 * time.Hour)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cp.cacheDir, cp.getCacheKey()+".json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCachedProviderFreshEntrySkipsVersionCheck(t *testing.T) {
+	stub := newVersionedStub("1", stubGroups("web1"))
+	cp := newTestCachedProvider(t, stub)
+
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stub.fetches != 1 {
+		t.Fatalf("fetches = %d after the first load, want 1", stub.fetches)
+	}
+
+	stub.versions, stub.version = 0, "2"
+	groups, err := cp.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stub.versions != 0 || stub.fetches != 1 {
+		t.Errorf("fresh cache made %d version checks and %d fetches, want none", stub.versions, stub.fetches-1)
+	}
+	if len(groups) != 1 || groups[0].Hosts[0].Name != "web1" {
+		t.Errorf("groups = %+v, want the cached groups", groups)
+	}
+}
+
+func TestCachedProviderExpiredEntryWithSameVersion(t *testing.T) {
+	stub := newVersionedStub("1", stubGroups("web1"))
+	cp := newTestCachedProvider(t, stub)
+
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expireEntry(t, cp)
+
+	stub.groups = stubGroups("web2")
+	groups, err := cp.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stub.fetches != 1 {
+		t.Errorf("fetches = %d, want the unchanged catalog to be served from the cache", stub.fetches)
+	}
+	if want := []string{"", "1"}; !reflect.DeepEqual(stub.since, want) {
+		t.Errorf("version checks since %q, want %q", stub.since, want)
+	}
+	if groups[0].Hosts[0].Name != "web1" {
+		t.Errorf("groups = %+v, want the cached groups", groups)
+	}
+	if entry := loadEntry(t, cp); time.Since(entry.Timestamp) > time.Minute {
+		t.Errorf("cache timestamp %s was not refreshed", entry.Timestamp)
+	}
+}
+
+func TestCachedProviderExpiredEntryWithNewVersion(t *testing.T) {
+	stub := newVersionedStub("1", stubGroups("web1"))
+	cp := newTestCachedProvider(t, stub)
+
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expireEntry(t, cp)
+
+	stub.version, stub.groups = "2", stubGroups("web2")
+	groups, err := cp.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stub.fetches != 2 || groups[0].Hosts[0].Name != "web2" {
+		t.Errorf("fetches = %d, groups = %+v, want a fresh fetch", stub.fetches, groups)
+	}
+	if entry := loadEntry(t, cp); entry.Version != "2" {
+		t.Errorf("cached version = %q, want 2", entry.Version)
+	}
+}
+
+func TestCachedProviderRecordsEmptyDiff(t *testing.T) {
+	stub := &stubProvider{groups: stubGroups("web1")}
+	cp := newTestCachedProvider(t, stub)
+
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expireEntry(t, cp)
+	stub.groups = stubGroups("web1", "web2")
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := cp.LastDiff(); !ok || len(diff.Added) != 1 {
+		t.Fatalf("diff = %+v, want web2 added", diff)
+	}
+
+	expireEntry(t, cp)
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := cp.LastDiff(); !ok || !diff.IsEmpty() {
+		t.Errorf("diff = %+v, want an empty diff after a refresh without changes", diff)
+	}
+}
+
+func TestCheckExpiredCachesDeclineRefreshes(t *testing.T) {
+	stub := &stubProvider{groups: stubGroups("web1")}
+	cp := newTestCachedProvider(t, stub)
+
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expireEntry(t, cp)
+
+	if err := checkExpiredCaches([]provider.Provider{cp}, strings.NewReader("\n")); err != nil {
+		t.Fatal(err)
+	}
+	if cp.useExpiredCache {
+		t.Fatal("declining the prompt kept the expired cache in use")
+	}
+
+	stub.groups = stubGroups("web1", "web2")
+	groups, err := cp.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stub.fetches != 2 || len(groups[0].Hosts) != 2 {
+		t.Fatalf("fetches = %d, groups = %+v, want a refreshed catalog", stub.fetches, groups)
+	}
+
+	diff, ok := cp.LastDiff()
+	if !ok || len(diff.Added) != 1 || diff.Added[0].Name != "web2" {
+		t.Errorf("diff = %+v, want web2 added", diff)
+	}
+}
+
+func TestCheckExpiredCachesAcceptKeepsEntry(t *testing.T) {
+	stub := &stubProvider{groups: stubGroups("web1")}
+	cp := newTestCachedProvider(t, stub)
+
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expireEntry(t, cp)
+
+	if err := checkExpiredCaches([]provider.Provider{cp}, strings.NewReader("y\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	stub.groups = stubGroups("web2")
+	groups, err := cp.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stub.fetches != 1 || groups[0].Hosts[0].Name != "web1" {
+		t.Errorf("fetches = %d, groups = %+v, want the expired cache", stub.fetches, groups)
+	}
+}
+
+func TestCheckExpiredCachesSkipsVersionedProviders(t *testing.T) {
+	stub := newVersionedStub("1", stubGroups("web1"))
+	cp := newTestCachedProvider(t, stub)
+
+	if _, err := cp.GetGroups(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expireEntry(t, cp)
+
+	input := strings.NewReader("y\n")
+	if err := checkExpiredCaches([]provider.Provider{cp}, input); err != nil {
+		t.Fatal(err)
+	}
+	if input.Len() == 0 || cp.useExpiredCache {
+		t.Error("versioned provider was prompted about its expired cache")
+	}
+}
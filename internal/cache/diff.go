@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type HostChange struct {
+	Name string      `json:"name"`
+	Old  *types.Host `json:"old"`
+	New  *types.Host `json:"new"`
+}
+
+type InventoryDiff struct {
+	Provider string        `json:"provider"`
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	Added    []*types.Host `json:"added,omitempty"`
+	Removed  []*types.Host `json:"removed,omitempty"`
+	Changed  []HostChange  `json:"changed,omitempty"`
+}
+
+func (d *InventoryDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (c HostChange) Describe() []string {
+	var changes []string
+
+	if c.Old.Hostname != c.New.Hostname {
+		changes = append(changes, fmt.Sprintf("hostname %s -> %s", c.Old.Hostname, c.New.Hostname))
+	}
+	if effectivePort(c.Old) != effectivePort(c.New) {
+		changes = append(changes, fmt.Sprintf("port %d -> %d", effectivePort(c.Old), effectivePort(c.New)))
+	}
+	if c.Old.User != c.New.User {
+		changes = append(changes, fmt.Sprintf("user %s -> %s", displayUser(c.Old.User), displayUser(c.New.User)))
+	}
+	if c.Old.Inactive != c.New.Inactive {
+		changes = append(changes, fmt.Sprintf("%s -> %s", displayState(c.Old.Inactive), displayState(c.New.Inactive)))
+	}
+
+	return changes
+}
+
+func ComputeDiff(providerName string, from time.Time, oldGroups []*types.Group, to time.Time, newGroups []*types.Group) *InventoryDiff {
+	diff := &InventoryDiff{
+		Provider: providerName,
+		From:     from,
+		To:       to,
+	}
+
+	oldHosts := hostsByName(oldGroups)
+	newHosts := hostsByName(newGroups)
+
+	for name, newHost := range newHosts {
+		oldHost, exists := oldHosts[name]
+		if !exists {
+			diff.Added = append(diff.Added, newHost)
+			continue
+		}
+
+		change := HostChange{Name: name, Old: oldHost, New: newHost}
+		if len(change.Describe()) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+
+	for name, oldHost := range oldHosts {
+		if _, exists := newHosts[name]; !exists {
+			diff.Removed = append(diff.Removed, oldHost)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+
+	return diff
+}
+
+func hostsByName(groups []*types.Group) map[string]*types.Host {
+	hosts := make(map[string]*types.Host)
+	for _, group := range groups {
+		for _, host := range group.AllHosts() {
+			if _, exists := hosts[host.Name]; !exists {
+				hosts[host.Name] = host
+			}
+		}
+	}
+	return hosts
+}
+
+func effectivePort(host *types.Host) int {
+	if host.Port > 0 {
+		return host.Port
+	}
+	return 22
+}
+
+func displayUser(user string) string {
+	if user == "" {
+		return "(default)"
+	}
+	return user
+}
+
+func displayState(inactive bool) string {
+	if inactive {
+		return "inactive"
+	}
+	return "active"
+}
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/tech-arch1tect/lssh/internal/bulk"
 	"github.com/tech-arch1tect/lssh/internal/provider"
+	"github.com/tech-arch1tect/lssh/pkg/types"
 )
 
 type ExclusionType int
@@ -19,11 +23,41 @@ const (
 )
 
 type Config struct {
-	Providers         []provider.Config `json:"providers"`
-	CacheEnabled      *bool             `json:"cache_enabled,omitempty"`
-	ExcludeGroups     []string          `json:"exclude_groups,omitempty"`
-	HardExcludeGroups []string          `json:"hard_exclude_groups,omitempty"`
-	ExcludeHosts      []string          `json:"exclude_hosts,omitempty"`
+	Providers         []provider.Config          `json:"providers"`
+	CacheEnabled      *bool                      `json:"cache_enabled,omitempty"`
+	ExcludeGroups     []string                   `json:"exclude_groups,omitempty"`
+	HardExcludeGroups []string                   `json:"hard_exclude_groups,omitempty"`
+	ExcludeHosts      []string                   `json:"exclude_hosts,omitempty"`
+	Offline           *bool                      `json:"offline,omitempty"`
+	FrecencySort      *bool                      `json:"frecency_sort,omitempty"`
+	SortMode          string                     `json:"sort_mode,omitempty"`
+	TableColumns      []string                   `json:"table_columns,omitempty"`
+	Probe             ProbeConfig                `json:"probe,omitempty"`
+	Transports        map[string]TransportConfig `json:"transports,omitempty"`
+	GroupTransports   map[string]string          `json:"group_transports,omitempty"`
+	Executor          string                     `json:"executor,omitempty"`
+	Bulk              BulkConfig                 `json:"bulk,omitempty"`
+}
+
+type BulkConfig struct {
+	Parallel    *int   `json:"parallel,omitempty"`
+	Batch       string `json:"batch,omitempty"`
+	Pause       string `json:"pause,omitempty"`
+	MaxFailures int    `json:"max_failures,omitempty"`
+	Timeout     string `json:"timeout,omitempty"`
+}
+
+type TransportConfig struct {
+	Interactive string `json:"interactive"`
+	Command     string `json:"command,omitempty"`
+}
+
+type ProbeConfig struct {
+	Enabled     *bool  `json:"enabled,omitempty"`
+	Banner      bool   `json:"banner,omitempty"`
+	Timeout     string `json:"timeout,omitempty"`
+	Concurrency int    `json:"concurrency,omitempty"`
+	CacheTTL    string `json:"cache_ttl,omitempty"`
 }
 
 func Load() (*Config, error) {
@@ -107,6 +141,8 @@ func getProviderType(filePath string) string {
 		return "ansible"
 	case ".json":
 		return "json"
+	case ".toml":
+		return "toml"
 	default:
 		return "json"
 	}
@@ -126,6 +162,181 @@ func (c *Config) IsCacheEnabled() bool {
 	return true
 }
 
+func (c *Config) IsOffline() bool {
+	if envValue := os.Getenv("LSSH_OFFLINE"); envValue != "" {
+		if offline, err := strconv.ParseBool(envValue); err == nil {
+			return offline
+		}
+	}
+
+	if c.Offline != nil {
+		return *c.Offline
+	}
+
+	return false
+}
+
+func (c *Config) IsFrecencySortEnabled() bool {
+	if envValue := os.Getenv("LSSH_FRECENCY_SORT"); envValue != "" {
+		if enabled, err := strconv.ParseBool(envValue); err == nil {
+			return enabled
+		}
+	}
+
+	if c.FrecencySort != nil {
+		return *c.FrecencySort
+	}
+
+	return false
+}
+
+func (c *Config) GetSortMode() string {
+	if envValue := os.Getenv("LSSH_SORT_MODE"); envValue != "" {
+		return envValue
+	}
+
+	if c.SortMode != "" {
+		return c.SortMode
+	}
+
+	if c.IsFrecencySortEnabled() {
+		return "recent"
+	}
+
+	return ""
+}
+
+func (c *Config) IsSortModeSet() bool {
+	return os.Getenv("LSSH_SORT_MODE") != "" || c.SortMode != ""
+}
+
+func (c *Config) GetTableColumns() []string {
+	if envValue := os.Getenv("LSSH_TABLE_COLUMNS"); envValue != "" {
+		return strings.Split(envValue, ",")
+	}
+	if len(c.TableColumns) > 0 {
+		return c.TableColumns
+	}
+	return []string{"name", "hostname", "port", "user", "groups", "provider", "last_connected"}
+}
+
+func (c *Config) IsProbeEnabled() bool {
+	if envValue := os.Getenv("LSSH_PROBE"); envValue != "" {
+		if enabled, err := strconv.ParseBool(envValue); err == nil {
+			return enabled
+		}
+	}
+
+	if c.Probe.Enabled != nil {
+		return *c.Probe.Enabled
+	}
+
+	return false
+}
+
+func (c *Config) GetProbeTimeout() time.Duration {
+	timeout := parseDuration(c.Probe.Timeout, 2*time.Second)
+	if envValue := os.Getenv("LSSH_PROBE_TIMEOUT"); envValue != "" {
+		return parseDuration(envValue, timeout)
+	}
+	return timeout
+}
+
+func (c *Config) GetProbeCacheTTL() time.Duration {
+	cacheTTL := parseDuration(c.Probe.CacheTTL, 30*time.Second)
+	if envValue := os.Getenv("LSSH_PROBE_CACHE_TTL"); envValue != "" {
+		return parseDuration(envValue, cacheTTL)
+	}
+	return cacheTTL
+}
+
+func (c *Config) GetProbeConcurrency() int {
+	if envValue := os.Getenv("LSSH_PROBE_CONCURRENCY"); envValue != "" {
+		if concurrency, err := strconv.Atoi(envValue); err == nil && concurrency > 0 {
+			return concurrency
+		}
+	}
+
+	if c.Probe.Concurrency > 0 {
+		return c.Probe.Concurrency
+	}
+	return 16
+}
+
+func (c *Config) GetExecutor() string {
+	if envValue := os.Getenv("LSSH_EXECUTOR"); envValue != "" {
+		return envValue
+	}
+	if c.Executor != "" {
+		return c.Executor
+	}
+	return "external"
+}
+
+func (c *Config) GetBulkOptions() bulk.Options {
+	settings := c.Bulk
+	if envValue := os.Getenv("LSSH_BULK_PARALLEL"); envValue != "" {
+		if parallel, err := strconv.Atoi(envValue); err == nil && parallel >= 0 {
+			settings.Parallel = &parallel
+		}
+	}
+	if envValue := os.Getenv("LSSH_BULK_BATCH"); envValue != "" {
+		if _, _, err := bulk.ParseBatch(envValue); err == nil {
+			settings.Batch = envValue
+		}
+	}
+	if envValue := os.Getenv("LSSH_BULK_PAUSE"); envValue != "" {
+		if duration, err := time.ParseDuration(envValue); err == nil && duration >= 0 {
+			settings.Pause = envValue
+		}
+	}
+	if envValue := os.Getenv("LSSH_BULK_MAX_FAILURES"); envValue != "" {
+		if maxFailures, err := strconv.Atoi(envValue); err == nil && maxFailures >= 0 {
+			settings.MaxFailures = maxFailures
+		}
+	}
+	if envValue := os.Getenv("LSSH_BULK_TIMEOUT"); envValue != "" {
+		if duration, err := time.ParseDuration(envValue); err == nil && duration >= 0 {
+			settings.Timeout = envValue
+		}
+	}
+
+	options := bulk.Options{
+		Parallel:    10,
+		Pause:       parseDuration(settings.Pause, 0),
+		MaxFailures: settings.MaxFailures,
+		Timeout:     parseOptionalDuration(settings.Timeout, 30*time.Second),
+	}
+	if settings.Parallel != nil {
+		options.Parallel = *settings.Parallel
+	}
+	if size, percent, err := bulk.ParseBatch(settings.Batch); err == nil {
+		options.BatchSize = size
+		options.BatchPercent = percent
+	}
+	return options
+}
+
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+		return duration
+	}
+	return fallback
+}
+
+func parseOptionalDuration(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return duration
+	}
+	return fallback
+}
+
 func (c *Config) GetExcludeGroups() []string {
 	if envValue := os.Getenv("LSSH_EXCLUDE_GROUPS"); envValue != "" {
 		return strings.Split(envValue, ",")
@@ -147,7 +358,7 @@ func (c *Config) GetExcludeHosts() []string {
 	return c.ExcludeHosts
 }
 
-func matchesPattern(name, pattern string) bool {
+func MatchesPattern(name, pattern string) bool {
 	if pattern == "" {
 		return false
 	}
@@ -196,16 +407,61 @@ func (c *Config) IsGroupExcluded(groupName string, exclusionType ExclusionType)
 	}
 
 	for _, pattern := range patterns {
-		if matchesPattern(groupName, strings.TrimSpace(pattern)) {
+		if MatchesPattern(groupName, strings.TrimSpace(pattern)) {
 			return true
 		}
 	}
 	return false
 }
 
+func (c *Config) GetGroupTransport(groupName string) string {
+	if transport, ok := c.GroupTransports[groupName]; ok {
+		return transport
+	}
+
+	var patterns []string
+	for pattern := range c.GroupTransports {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		if MatchesPattern(groupName, pattern) {
+			return c.GroupTransports[pattern]
+		}
+	}
+	return ""
+}
+
+func (c *Config) ApplyGroupTransports(groups []*types.Group) {
+	c.applyGroupTransports(groups, "")
+}
+
+func (c *Config) applyGroupTransports(groups []*types.Group, inherited string) {
+	for _, group := range groups {
+		transport := inherited
+		if group.Transport != "" {
+			transport = group.Transport
+		}
+		if configured := c.GetGroupTransport(group.Name); configured != "" {
+			transport = configured
+		}
+
+		if transport != "" {
+			for _, host := range group.Hosts {
+				if host.Transport == "" {
+					host.Transport = transport
+				}
+			}
+		}
+
+		c.applyGroupTransports(group.SubGroups, transport)
+	}
+}
+
 func (c *Config) IsHostExcluded(hostName string) bool {
 	for _, pattern := range c.GetExcludeHosts() {
-		if matchesPattern(hostName, strings.TrimSpace(pattern)) {
+		if MatchesPattern(hostName, strings.TrimSpace(pattern)) {
 			return true
 		}
 	}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/internal/bulk"
+)
+
+func TestProbeSettings(t *testing.T) {
+	tests := []struct {
+		name            string
+		probe           ProbeConfig
+		env             map[string]string
+		wantTimeout     time.Duration
+		wantCacheTTL    time.Duration
+		wantConcurrency int
+	}{
+		{
+			name:            "defaults",
+			wantTimeout:     2 * time.Second,
+			wantCacheTTL:    30 * time.Second,
+			wantConcurrency: 16,
+		},
+		{
+			name:            "config values",
+			probe:           ProbeConfig{Timeout: "500ms", CacheTTL: "1m", Concurrency: 4},
+			wantTimeout:     500 * time.Millisecond,
+			wantCacheTTL:    time.Minute,
+			wantConcurrency: 4,
+		},
+		{
+			name:  "environment overrides config",
+			probe: ProbeConfig{Timeout: "500ms", CacheTTL: "1m", Concurrency: 4},
+			env: map[string]string{
+				"LSSH_PROBE_TIMEOUT":     "3s",
+				"LSSH_PROBE_CACHE_TTL":   "10s",
+				"LSSH_PROBE_CONCURRENCY": "32",
+			},
+			wantTimeout:     3 * time.Second,
+			wantCacheTTL:    10 * time.Second,
+			wantConcurrency: 32,
+		},
+		{
+			name:  "invalid environment values fall back to config",
+			probe: ProbeConfig{Timeout: "500ms", CacheTTL: "1m", Concurrency: 4},
+			env: map[string]string{
+				"LSSH_PROBE_TIMEOUT":     "soon",
+				"LSSH_PROBE_CACHE_TTL":   "-1s",
+				"LSSH_PROBE_CONCURRENCY": "0",
+			},
+			wantTimeout:     500 * time.Millisecond,
+			wantCacheTTL:    time.Minute,
+			wantConcurrency: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range []string{"LSSH_PROBE_TIMEOUT", "LSSH_PROBE_CACHE_TTL", "LSSH_PROBE_CONCURRENCY"} {
+				t.Setenv(name, test.env[name])
+			}
+
+			config := &Config{Probe: test.probe}
+			if got := config.GetProbeTimeout(); got != test.wantTimeout {
+				t.Errorf("GetProbeTimeout() = %s, want %s", got, test.wantTimeout)
+			}
+			if got := config.GetProbeCacheTTL(); got != test.wantCacheTTL {
+				t.Errorf("GetProbeCacheTTL() = %s, want %s", got, test.wantCacheTTL)
+			}
+			if got := config.GetProbeConcurrency(); got != test.wantConcurrency {
+				t.Errorf("GetProbeConcurrency() = %d, want %d", got, test.wantConcurrency)
+			}
+		})
+	}
+}
+
+func TestBulkOptions(t *testing.T) {
+	parallel := 0
+
+	tests := []struct {
+		name string
+		bulk BulkConfig
+		env  map[string]string
+		want bulk.Options
+	}{
+		{
+			name: "defaults",
+			want: bulk.Options{Parallel: 10, Timeout: 30 * time.Second},
+		},
+		{
+			name: "config values",
+			bulk: BulkConfig{Parallel: &parallel, Batch: "10%", Pause: "30s", MaxFailures: 1, Timeout: "5m"},
+			want: bulk.Options{Parallel: 0, BatchPercent: 10, Pause: 30 * time.Second, MaxFailures: 1, Timeout: 5 * time.Minute},
+		},
+		{
+			name: "zero timeout disables it",
+			bulk: BulkConfig{Timeout: "0s"},
+			want: bulk.Options{Parallel: 10},
+		},
+		{
+			name: "bare zero timeout disables it",
+			bulk: BulkConfig{Timeout: "0"},
+			want: bulk.Options{Parallel: 10},
+		},
+		{
+			name: "invalid timeout keeps the default",
+			bulk: BulkConfig{Timeout: "-1s"},
+			want: bulk.Options{Parallel: 10, Timeout: 30 * time.Second},
+		},
+		{
+			name: "environment overrides config",
+			bulk: BulkConfig{Parallel: &parallel, Batch: "10%", Pause: "30s", MaxFailures: 1, Timeout: "5m"},
+			env: map[string]string{
+				"LSSH_BULK_PARALLEL":     "3",
+				"LSSH_BULK_BATCH":        "5",
+				"LSSH_BULK_PAUSE":        "1m",
+				"LSSH_BULK_MAX_FAILURES": "2",
+				"LSSH_BULK_TIMEOUT":      "0",
+			},
+			want: bulk.Options{Parallel: 3, BatchSize: 5, Pause: time.Minute, MaxFailures: 2},
+		},
+		{
+			name: "invalid environment values fall back to config",
+			bulk: BulkConfig{Batch: "10%", Pause: "30s", MaxFailures: 1, Timeout: "5m"},
+			env: map[string]string{
+				"LSSH_BULK_PARALLEL":     "many",
+				"LSSH_BULK_BATCH":        "half",
+				"LSSH_BULK_PAUSE":        "-1s",
+				"LSSH_BULK_MAX_FAILURES": "-1",
+				"LSSH_BULK_TIMEOUT":      "later",
+			},
+			want: bulk.Options{Parallel: 10, BatchPercent: 10, Pause: 30 * time.Second, MaxFailures: 1, Timeout: 5 * time.Minute},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range []string{"LSSH_BULK_PARALLEL", "LSSH_BULK_BATCH", "LSSH_BULK_PAUSE", "LSSH_BULK_MAX_FAILURES", "LSSH_BULK_TIMEOUT"} {
+				t.Setenv(name, test.env[name])
+			}
+
+			config := &Config{Bulk: test.bulk}
+			if got := config.GetBulkOptions(); got != test.want {
+				t.Errorf("GetBulkOptions() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSortMode(t *testing.T) {
+	enabled := true
+
+	tests := []struct {
+		name     string
+		config   Config
+		env      string
+		wantMode string
+		wantSet  bool
+	}{
+		{"unset", Config{}, "", "", false},
+		{"frecency sort is not an explicit mode", Config{FrecencySort: &enabled}, "", "recent", false},
+		{"config", Config{SortMode: "name"}, "", "name", true},
+		{"environment overrides config", Config{SortMode: "name"}, "provider", "provider", true},
+		{"environment alone", Config{}, "hostname", "hostname", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("LSSH_SORT_MODE", test.env)
+			t.Setenv("LSSH_FRECENCY_SORT", "")
+
+			if got := test.config.GetSortMode(); got != test.wantMode {
+				t.Errorf("GetSortMode() = %q, want %q", got, test.wantMode)
+			}
+			if got := test.config.IsSortModeSet(); got != test.wantSet {
+				t.Errorf("IsSortModeSet() = %v, want %v", got, test.wantSet)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package probe
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusUp
+	StatusDown
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusUp:
+		return "up"
+	case StatusDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+type Result struct {
+	Status    Status
+	Latency   time.Duration
+	Banner    string
+	Err       error
+	CheckedAt time.Time
+}
+
+type Prober struct {
+	timeout     time.Duration
+	cacheTTL    time.Duration
+	concurrency int
+	readBanner  bool
+
+	mu       sync.Mutex
+	results  map[string]Result
+	inFlight map[string]bool
+}
+
+func NewProber(timeout, cacheTTL time.Duration, concurrency int, readBanner bool) *Prober {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &Prober{
+		timeout:     timeout,
+		cacheTTL:    cacheTTL,
+		concurrency: concurrency,
+		readBanner:  readBanner,
+		results:     make(map[string]Result),
+		inFlight:    make(map[string]bool),
+	}
+}
+
+func (p *Prober) Result(host *types.Host) (Result, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result, ok := p.results[host.Key()]
+	return result, ok
+}
+
+func (p *Prober) Claim(hosts []*types.Host, force bool) []*types.Host {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var claimed []*types.Host
+	for _, host := range hosts {
+		key := host.Key()
+		if p.inFlight[key] {
+			continue
+		}
+		if result, ok := p.results[key]; ok && !force && time.Since(result.CheckedAt) < p.cacheTTL {
+			continue
+		}
+		p.inFlight[key] = true
+		claimed = append(claimed, host)
+	}
+	return claimed
+}
+
+func (p *Prober) Probe(ctx context.Context, hosts []*types.Host) {
+	semaphore := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host *types.Host) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result := p.probeHost(ctx, host)
+
+			p.mu.Lock()
+			p.results[host.Key()] = result
+			delete(p.inFlight, host.Key())
+			p.mu.Unlock()
+		}(host)
+	}
+
+	wg.Wait()
+}
+
+func (p *Prober) probeHost(ctx context.Context, host *types.Host) Result {
+	port := host.Port
+	if port <= 0 {
+		port = 22
+	}
+	address := net.JoinHostPort(host.Hostname, strconv.Itoa(port))
+
+	dialer := net.Dialer{Timeout: p.timeout}
+	started := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return Result{Status: StatusDown, Err: err, CheckedAt: time.Now()}
+	}
+	defer conn.Close()
+
+	result := Result{
+		Status:    StatusUp,
+		Latency:   time.Since(started),
+		CheckedAt: time.Now(),
+	}
+
+	if p.readBanner {
+		banner, err := readBanner(conn, p.timeout)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to read SSH banner: %w", err)
+		}
+		result.Banner = banner
+	}
+
+	return result
+}
+
+func readBanner(conn net.Conn, timeout time.Duration) (string, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return "", err
+	}
+
+	reader := bufio.NewReaderSize(conn, 256)
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			return line, nil
+		}
+		if err != nil {
+			return line, err
+		}
+	}
+}
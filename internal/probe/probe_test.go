@@ -0,0 +1,257 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+func startServer(t *testing.T) (*types.Host, <-chan net.Conn) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	conns := make(chan net.Conn, 16)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(conns)
+				return
+			}
+			conns <- conn
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return &types.Host{Name: "server", Hostname: host, Port: portNumber}, conns
+}
+
+func closedPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	return port
+}
+
+func TestClaim(t *testing.T) {
+	web := &types.Host{Name: "web", Hostname: "web.example.com"}
+	db := &types.Host{Name: "db", Hostname: "db.example.com"}
+	stale := &types.Host{Name: "stale", Hostname: "stale.example.com"}
+
+	prober := NewProber(time.Second, time.Minute, 4, false)
+	prober.results[db.Key()] = Result{Status: StatusUp, CheckedAt: time.Now()}
+	prober.results[stale.Key()] = Result{Status: StatusUp, CheckedAt: time.Now().Add(-2 * time.Minute)}
+
+	tests := []struct {
+		name  string
+		force bool
+		want  []string
+	}{
+		{"fresh results are skipped", false, []string{"web", "stale"}},
+		{"in-flight hosts are skipped", false, nil},
+		{"in-flight hosts are skipped when forced", true, []string{"db"}},
+	}
+
+	for _, test := range tests {
+		claimed := prober.Claim([]*types.Host{web, db, stale}, test.force)
+		var names []string
+		for _, host := range claimed {
+			names = append(names, host.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(test.want, ",") {
+			t.Errorf("%s: claimed %v, want %v", test.name, names, test.want)
+		}
+	}
+}
+
+func TestNewProberConcurrency(t *testing.T) {
+	for _, concurrency := range []int{-1, 0} {
+		if prober := NewProber(time.Second, time.Minute, concurrency, false); prober.concurrency != 1 {
+			t.Errorf("NewProber(concurrency %d).concurrency = %d, want 1", concurrency, prober.concurrency)
+		}
+	}
+}
+
+func TestProbeLimitsConcurrency(t *testing.T) {
+	server, conns := startServer(t)
+
+	var hosts []*types.Host
+	for i := 0; i < 5; i++ {
+		hosts = append(hosts, &types.Host{Name: "host" + strconv.Itoa(i), Hostname: server.Hostname, Port: server.Port, User: "user" + strconv.Itoa(i)})
+	}
+
+	prober := NewProber(5*time.Second, time.Minute, 2, true)
+	claimed := prober.Claim(hosts, false)
+	done := make(chan struct{})
+	go func() {
+		prober.Probe(context.Background(), claimed)
+		close(done)
+	}()
+
+	var open []net.Conn
+	for len(open) < 2 {
+		open = append(open, <-conns)
+	}
+	select {
+	case <-conns:
+		t.Fatal("a third host was probed while two probes were waiting for a banner")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	for answered := 0; answered < len(hosts); answered++ {
+		if len(open) == 0 {
+			open = append(open, <-conns)
+		}
+		if _, err := open[0].Write([]byte("SSH-2.0-OpenSSH_9.6\r\n")); err != nil {
+			t.Fatal(err)
+		}
+		open = open[1:]
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Probe did not return")
+	}
+
+	for _, host := range hosts {
+		result, ok := prober.Result(host)
+		if !ok || result.Status != StatusUp || result.Banner != "SSH-2.0-OpenSSH_9.6" {
+			t.Errorf("%s: result = %+v, %v", host.Name, result, ok)
+		}
+	}
+	if again := prober.Claim(hosts, false); len(again) != 0 {
+		t.Errorf("%d hosts were claimed again within the cache TTL", len(again))
+	}
+}
+
+func TestProbeBanner(t *testing.T) {
+	tests := []struct {
+		name       string
+		send       string
+		wantBanner string
+		wantErr    string
+	}{
+		{"banner", "SSH-2.0-OpenSSH_9.6\r\n", "SSH-2.0-OpenSSH_9.6", ""},
+		{"lines before the banner", "Welcome\r\nauthorised use only\r\nSSH-2.0-dropbear\r\n", "SSH-2.0-dropbear", ""},
+		{"banner without a newline", "SSH-2.0-tiny", "SSH-2.0-tiny", ""},
+		{"not SSH", "HTTP/1.1 400 Bad Request\r\n", "", "failed to read SSH banner"},
+		{"silent server", "", "", "failed to read SSH banner"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			host, conns := startServer(t)
+			go func() {
+				conn := <-conns
+				conn.Write([]byte(test.send))
+				if test.send != "" {
+					conn.Close()
+				}
+			}()
+
+			prober := NewProber(200*time.Millisecond, time.Minute, 1, true)
+			result := prober.probeHost(context.Background(), host)
+
+			if result.Status != StatusUp {
+				t.Errorf("status = %s, want up", result.Status)
+			}
+			if result.Banner != test.wantBanner {
+				t.Errorf("banner = %q, want %q", result.Banner, test.wantBanner)
+			}
+			if test.wantErr == "" && result.Err != nil {
+				t.Errorf("error = %v", result.Err)
+			}
+			if test.wantErr != "" && (result.Err == nil || !strings.Contains(result.Err.Error(), test.wantErr)) {
+				t.Errorf("error = %v, want %q", result.Err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestProbeWithoutBanner(t *testing.T) {
+	host, _ := startServer(t)
+
+	result := NewProber(time.Second, time.Minute, 1, false).probeHost(context.Background(), host)
+	if result.Status != StatusUp || result.Err != nil || result.Banner != "" {
+		t.Errorf("result = %+v, want up without reading a banner", result)
+	}
+	if result.Latency <= 0 || result.CheckedAt.IsZero() {
+		t.Errorf("result = %+v, want the latency and check time recorded", result)
+	}
+}
+
+func TestProbeDown(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		host *types.Host
+	}{
+		{"connection refused", context.Background(), &types.Host{Hostname: "127.0.0.1", Port: closedPort(t)}},
+		{"cancelled", cancelled, &types.Host{Hostname: "127.0.0.1", Port: closedPort(t)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			started := time.Now()
+			result := NewProber(100*time.Millisecond, time.Minute, 1, true).probeHost(test.ctx, test.host)
+
+			if result.Status != StatusDown || result.Err == nil {
+				t.Errorf("result = %+v, want down with an error", result)
+			}
+			if elapsed := time.Since(started); elapsed > 2*time.Second {
+				t.Errorf("probe took %s, want it bounded by the timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestProbeTimeout(t *testing.T) {
+	host := &types.Host{Hostname: "10.255.255.1", Port: 22}
+
+	started := time.Now()
+	result := NewProber(100*time.Millisecond, time.Minute, 1, true).probeHost(context.Background(), host)
+
+	if result.Err == nil {
+		t.Errorf("result = %+v, want the unroutable address to time out", result)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("probe took %s, want it bounded by the timeout", elapsed)
+	}
+}
+
+func TestStatusString(t *testing.T) {
+	tests := []struct {
+		status Status
+		want   string
+	}{
+		{StatusUnknown, "unknown"},
+		{StatusUp, "up"},
+		{StatusDown, "down"},
+	}
+
+	for _, test := range tests {
+		if got := test.status.String(); got != test.want {
+			t.Errorf("Status(%d).String() = %q, want %q", test.status, got, test.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os/exec"
 	"os/user"
+	"sort"
 
 	"github.com/tech-arch1tect/lssh/pkg/types"
 )
@@ -114,6 +115,10 @@ func (p *AnsibleProvider) GetGroups(ctx context.Context) ([]*types.Group, error)
 		}
 	}
 
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
 	totalHosts := 0
 	for _, group := range groups {
 		totalHosts += len(group.AllHosts())
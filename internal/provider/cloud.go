@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type CloudOptions struct {
+	File    string
+	Command []string
+	Address string
+	GroupBy string
+	Stopped string
+	User    string
+	Port    int
+}
+
+type cloudInstance struct {
+	name       string
+	privateIP  string
+	publicIP   string
+	running    bool
+	state      string
+	groupNames map[string]string
+	labels     map[string]string
+	tags       []string
+}
+
+func (o CloudOptions) validate() error {
+	switch o.Address {
+	case "", "private", "public":
+	default:
+		return fmt.Errorf("'address' must be private or public, got %q", o.Address)
+	}
+
+	switch o.Stopped {
+	case "", "dim", "exclude":
+	default:
+		return fmt.Errorf("'stopped' must be dim or exclude, got %q", o.Stopped)
+	}
+
+	if o.GroupBy != "" && o.GroupBy != "vpc" && o.GroupBy != "az" && !strings.HasPrefix(o.GroupBy, "tag:") {
+		return fmt.Errorf("'group_by' must be vpc, az or tag:<key>, got %q", o.GroupBy)
+	}
+
+	return nil
+}
+
+func (o CloudOptions) source() string {
+	if o.File != "" {
+		return o.File
+	}
+	return strings.Join(o.Command, " ")
+}
+
+func loadCloudOutput(ctx context.Context, options CloudOptions) ([]byte, error) {
+	if options.File != "" {
+		data, err := os.ReadFile(options.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", options.File, err)
+		}
+		return data, nil
+	}
+
+	if len(options.Command) == 0 {
+		return nil, fmt.Errorf("no file or command configured")
+	}
+
+	cmd := exec.CommandContext(ctx, options.Command[0], options.Command[1:]...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("%s command failed: %s", options.Command[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to run %s: %w", options.Command[0], err)
+	}
+
+	return output, nil
+}
+
+func buildCloudGroups(instances []cloudInstance, options CloudOptions) []*types.Group {
+	var groups []*types.Group
+	groupsByName := make(map[string]*types.Group)
+
+	for _, instance := range instances {
+		if !instance.running && options.Stopped == "exclude" {
+			continue
+		}
+
+		hostname := instance.privateIP
+		if (options.Address == "public" && instance.publicIP != "") || hostname == "" {
+			hostname = instance.publicIP
+		}
+		if hostname == "" {
+			continue
+		}
+
+		host := &types.Host{
+			Name:     instance.name,
+			Hostname: hostname,
+			User:     options.User,
+			Port:     options.Port,
+			Tags:     append([]string{"state=" + instance.state}, instance.tags...),
+			Inactive: !instance.running,
+		}
+
+		groupName := cloudGroupName(instance, options.GroupBy)
+		group, exists := groupsByName[groupName]
+		if !exists {
+			group = &types.Group{Name: groupName, Hosts: []*types.Host{}}
+			groupsByName[groupName] = group
+			groups = append(groups, group)
+		}
+		group.Hosts = append(group.Hosts, host)
+	}
+
+	return groups
+}
+
+func cloudGroupName(instance cloudInstance, groupBy string) string {
+	if strings.HasPrefix(groupBy, "tag:") {
+		if value := instance.labels[strings.TrimPrefix(groupBy, "tag:")]; value != "" {
+			return value
+		}
+		return "untagged"
+	}
+
+	if groupBy == "" {
+		groupBy = "vpc"
+	}
+	if name := instance.groupNames[groupBy]; name != "" {
+		return name
+	}
+	return "unknown " + groupBy
+}
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func cloudSummary(t *testing.T, provider Provider) map[string][]string {
+	t.Helper()
+
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := make(map[string][]string)
+	for _, group := range groups {
+		for _, host := range group.Hosts {
+			entry := host.Name + "=" + host.Hostname
+			if host.Inactive {
+				entry += " (inactive)"
+			}
+			summary[group.Name] = append(summary[group.Name], entry)
+		}
+	}
+	return summary
+}
+
+func TestEC2Provider(t *testing.T) {
+	file := filepath.Join("testdata", "ec2-describe-instances.json")
+
+	tests := []struct {
+		name    string
+		options CloudOptions
+		want    map[string][]string
+	}{
+		{
+			name: "defaults group by VPC and dim stopped instances",
+			want: map[string][]string{
+				"vpc-0aaa": {"web-1=10.0.1.10", "web-2=10.0.2.11 (inactive)", "worker-1=10.0.3.12 (inactive)"},
+				"vpc-0bbb": {"i-0a1b2c3d4e5f60003=10.1.0.5"},
+			},
+		},
+		{
+			name:    "public addresses fall back to private ones",
+			options: CloudOptions{Address: "public"},
+			want: map[string][]string{
+				"vpc-0aaa": {"web-1=203.0.113.10", "web-2=10.0.2.11 (inactive)", "worker-1=10.0.3.12 (inactive)"},
+				"vpc-0bbb": {"i-0a1b2c3d4e5f60003=10.1.0.5"},
+			},
+		},
+		{
+			name:    "grouped by availability zone",
+			options: CloudOptions{GroupBy: "az"},
+			want: map[string][]string{
+				"eu-west-1a": {"web-1=10.0.1.10", "i-0a1b2c3d4e5f60003=10.1.0.5"},
+				"eu-west-1b": {"web-2=10.0.2.11 (inactive)"},
+				"eu-west-1c": {"worker-1=10.0.3.12 (inactive)"},
+			},
+		},
+		{
+			name:    "grouped by tag",
+			options: CloudOptions{GroupBy: "tag:Team"},
+			want: map[string][]string{
+				"frontend": {"web-1=10.0.1.10", "web-2=10.0.2.11 (inactive)"},
+				"untagged": {"i-0a1b2c3d4e5f60003=10.1.0.5", "worker-1=10.0.3.12 (inactive)"},
+			},
+		},
+		{
+			name:    "stopped instances excluded",
+			options: CloudOptions{Stopped: "exclude"},
+			want: map[string][]string{
+				"vpc-0aaa": {"web-1=10.0.1.10"},
+				"vpc-0bbb": {"i-0a1b2c3d4e5f60003=10.1.0.5"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.options.File = file
+			if got := cloudSummary(t, NewEC2Provider("ec2", test.options)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("groups = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEC2HostDetails(t *testing.T) {
+	provider := NewEC2Provider("ec2", CloudOptions{File: filepath.Join("testdata", "ec2-describe-instances.json"), User: "ec2-user", Port: 2222})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := groups[0].Hosts[0]
+	if host.User != "ec2-user" || host.Port != 2222 {
+		t.Errorf("host = %+v, want the configured user and port", host)
+	}
+	if want := []string{"state=running", "instance=i-0a1b2c3d4e5f60001", "Name=web-1", "Team=frontend"}; !reflect.DeepEqual(host.Tags, want) {
+		t.Errorf("tags = %v, want %v", host.Tags, want)
+	}
+}
+
+func TestGCEProvider(t *testing.T) {
+	file := filepath.Join("testdata", "gce-instances-list.json")
+
+	tests := []struct {
+		name    string
+		options CloudOptions
+		want    map[string][]string
+	}{
+		{
+			name: "defaults group by network and dim stopped instances",
+			want: map[string][]string{
+				"default": {"api-1=10.132.0.2"},
+				"batch":   {"batch-1=10.140.0.7 (inactive)"},
+			},
+		},
+		{
+			name:    "public addresses",
+			options: CloudOptions{Address: "public"},
+			want: map[string][]string{
+				"default": {"api-1=34.76.0.2"},
+				"batch":   {"batch-1=10.140.0.7 (inactive)"},
+			},
+		},
+		{
+			name:    "grouped by zone",
+			options: CloudOptions{GroupBy: "az"},
+			want: map[string][]string{
+				"europe-west1-b": {"api-1=10.132.0.2"},
+				"europe-west1-c": {"batch-1=10.140.0.7 (inactive)"},
+			},
+		},
+		{
+			name:    "grouped by label",
+			options: CloudOptions{GroupBy: "tag:team"},
+			want: map[string][]string{
+				"backend": {"api-1=10.132.0.2"},
+				"data":    {"batch-1=10.140.0.7 (inactive)"},
+			},
+		},
+		{
+			name:    "stopped instances excluded",
+			options: CloudOptions{Stopped: "exclude"},
+			want: map[string][]string{
+				"default": {"api-1=10.132.0.2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.options.File = file
+			if got := cloudSummary(t, NewGCEProvider("gce", test.options)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("groups = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGCEHostTags(t *testing.T) {
+	groups, err := NewGCEProvider("gce", CloudOptions{File: filepath.Join("testdata", "gce-instances-list.json")}).GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"state=RUNNING", "env=prod", "network-tag=http-server", "network-tag=ssh", "team=backend"}
+	if tags := groups[0].Hosts[0].Tags; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+}
+
+func TestCloudProviderCommand(t *testing.T) {
+	fakeCommand(t, "aws", `if [ "$*" != "ec2 describe-instances --output json --region eu-west-1" ]; then
+  echo "unexpected arguments: $*" >&2
+  exit 2
+fi
+cat testdata/ec2-describe-instances.json
+`)
+
+	provider := NewEC2Provider("ec2", CloudOptions{Command: []string{"aws", "ec2", "describe-instances", "--output", "json", "--region", "eu-west-1"}})
+	if got := cloudSummary(t, provider); len(got["vpc-0aaa"]) != 3 {
+		t.Errorf("groups = %v, want the instances printed by the command", got)
+	}
+
+	provider = NewEC2Provider("ec2", CloudOptions{Command: []string{"aws", "ec2", "describe-instances"}})
+	_, err := provider.GetGroups(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "unexpected arguments") {
+		t.Errorf("error = %v, want the command's stderr", err)
+	}
+}
+
+func TestCloudOptionsValidate(t *testing.T) {
+	tests := []struct {
+		options CloudOptions
+		wantErr bool
+	}{
+		{CloudOptions{}, false},
+		{CloudOptions{Address: "public", Stopped: "exclude", GroupBy: "tag:Team"}, false},
+		{CloudOptions{GroupBy: "az"}, false},
+		{CloudOptions{Address: "ipv6"}, true},
+		{CloudOptions{Stopped: "hide"}, true},
+		{CloudOptions{GroupBy: "region"}, true},
+	}
+
+	for _, test := range tests {
+		if err := test.options.validate(); (err != nil) != test.wantErr {
+			t.Errorf("validate(%+v) = %v, wantErr %v", test.options, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type ConsulProvider struct {
+	name    string
+	options ConsulOptions
+	client  *http.Client
+}
+
+type ConsulOptions struct {
+	Address     string
+	Token       string
+	Datacenters []string
+	Wait        time.Duration
+	User        string
+	Port        int
+}
+
+type consulNode struct {
+	Node       string            `json:"Node"`
+	Address    string            `json:"Address"`
+	Datacenter string            `json:"Datacenter"`
+	Meta       map[string]string `json:"Meta"`
+}
+
+func NewConsulProvider(name string, options ConsulOptions) *ConsulProvider {
+	if options.Address == "" {
+		options.Address = "http://127.0.0.1:8500"
+	}
+	if !strings.Contains(options.Address, "://") {
+		options.Address = "http://" + options.Address
+	}
+	options.Address = strings.TrimRight(options.Address, "/")
+
+	if options.Wait <= 0 {
+		options.Wait = time.Second
+	}
+
+	return &ConsulProvider{
+		name:    name,
+		options: options,
+		client:  &http.Client{Timeout: options.Wait + 10*time.Second},
+	}
+}
+
+func (p *ConsulProvider) Name() string {
+	return p.name
+}
+
+func (p *ConsulProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	datacenters, err := p.datacenters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dcGroups, serviceGroups []*types.Group
+	for _, dc := range datacenters {
+		var nodes []consulNode
+		if _, err := p.get(ctx, "/v1/catalog/nodes", dc, "", &nodes); err != nil {
+			return nil, err
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+
+		hostsByNode := make(map[string]*types.Host)
+		dcGroup := &types.Group{Name: dc, Description: "Consul datacenter", Hosts: []*types.Host{}}
+		if dc == "" {
+			dcGroup.Name = nodes[0].Datacenter
+		}
+
+		for _, node := range nodes {
+			host := p.nodeHost(node)
+			hostsByNode[node.Node] = host
+			dcGroup.Hosts = append(dcGroup.Hosts, host)
+		}
+		dcGroups = append(dcGroups, dcGroup)
+
+		var services map[string][]string
+		if _, err := p.get(ctx, "/v1/catalog/services", dc, "", &services); err != nil {
+			return nil, err
+		}
+
+		serviceNames := make([]string, 0, len(services))
+		for service := range services {
+			serviceNames = append(serviceNames, service)
+		}
+		sort.Strings(serviceNames)
+
+		for _, service := range serviceNames {
+			var instances []consulNode
+			if _, err := p.get(ctx, "/v1/catalog/service/"+url.PathEscape(service), dc, "", &instances); err != nil {
+				return nil, err
+			}
+
+			groupName := service
+			if len(datacenters) > 1 {
+				groupName = dcGroup.Name + "/" + service
+			}
+			group := &types.Group{Name: groupName, Description: "Consul service", Hosts: []*types.Host{}}
+
+			seen := make(map[string]bool)
+			for _, instance := range instances {
+				if seen[instance.Node] {
+					continue
+				}
+				seen[instance.Node] = true
+
+				host, ok := hostsByNode[instance.Node]
+				if !ok {
+					host = p.nodeHost(instance)
+				}
+				group.Hosts = append(group.Hosts, host)
+			}
+
+			if len(group.Hosts) > 0 {
+				serviceGroups = append(serviceGroups, group)
+			}
+		}
+	}
+
+	if len(dcGroups) == 0 {
+		return nil, fmt.Errorf("no nodes found in Consul catalog at %s", p.options.Address)
+	}
+
+	return append(dcGroups, serviceGroups...), nil
+}
+
+func (p *ConsulProvider) Version(ctx context.Context, since string) (string, error) {
+	datacenters, err := p.datacenters(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	previous := make(map[string]string)
+	for _, part := range strings.Split(since, ",") {
+		if key, index, ok := strings.Cut(part, "="); ok {
+			previous[key] = index
+		}
+	}
+
+	var parts []string
+	blocked := false
+	for _, dc := range datacenters {
+		for _, endpoint := range []string{"/v1/catalog/nodes", "/v1/catalog/services"} {
+			key := dc + endpoint
+
+			index := ""
+			if !blocked {
+				index = previous[key]
+				blocked = index != ""
+			}
+
+			current, err := p.get(ctx, endpoint, dc, index, nil)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, key+"="+current)
+		}
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+func (p *ConsulProvider) source() string {
+	return p.options.Address + "?dc=" + strings.Join(p.options.Datacenters, ",")
+}
+
+func (p *ConsulProvider) datacenters(ctx context.Context) ([]string, error) {
+	if len(p.options.Datacenters) == 0 {
+		return []string{""}, nil
+	}
+
+	if len(p.options.Datacenters) == 1 && p.options.Datacenters[0] == "*" {
+		var datacenters []string
+		if _, err := p.get(ctx, "/v1/catalog/datacenters", "", "", &datacenters); err != nil {
+			return nil, err
+		}
+		return datacenters, nil
+	}
+
+	return p.options.Datacenters, nil
+}
+
+func (p *ConsulProvider) nodeHost(node consulNode) *types.Host {
+	host := &types.Host{
+		Name:     node.Node,
+		Hostname: node.Address,
+		User:     p.options.User,
+		Port:     p.options.Port,
+		Tags:     []string{"dc=" + node.Datacenter},
+	}
+
+	var meta []string
+	for key, value := range node.Meta {
+		meta = append(meta, key+"="+value)
+	}
+	sort.Strings(meta)
+	host.Tags = append(host.Tags, meta...)
+
+	return host
+}
+
+func (p *ConsulProvider) get(ctx context.Context, path, dc, index string, result interface{}) (string, error) {
+	query := url.Values{}
+	if dc != "" {
+		query.Set("dc", dc)
+	}
+	if index != "" {
+		query.Set("index", index)
+		query.Set("wait", p.options.Wait.String())
+	}
+
+	requestURL := p.options.Address + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Consul request: %w", err)
+	}
+	if p.options.Token != "" {
+		req.Header.Set("X-Consul-Token", p.options.Token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query Consul at %s: %w", p.options.Address, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("consul request %s failed: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return "", fmt.Errorf("failed to parse Consul response from %s: %w", path, err)
+		}
+	}
+
+	return resp.Header.Get("X-Consul-Index"), nil
+}
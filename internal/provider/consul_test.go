@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type consulCatalog struct {
+	nodes    map[string][]consulNode
+	services map[string]map[string][]string
+	index    map[string]string
+}
+
+type consulStandIn struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newConsulStandIn(t *testing.T, catalog consulCatalog) *consulStandIn {
+	t.Helper()
+
+	standIn := &consulStandIn{}
+	standIn.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		standIn.requests = append(standIn.requests, r.URL.RequestURI())
+		standIn.mu.Unlock()
+
+		if r.Header.Get("X-Consul-Token") != "secret" {
+			http.Error(w, "ACL not found", http.StatusForbidden)
+			return
+		}
+
+		dc := r.URL.Query().Get("dc")
+		if dc == "" {
+			dc = "dc1"
+		}
+		w.Header().Set("X-Consul-Index", catalog.index[dc+r.URL.Path])
+
+		var body interface{}
+		switch {
+		case r.URL.Path == "/v1/catalog/datacenters":
+			var datacenters []string
+			for name := range catalog.nodes {
+				datacenters = append(datacenters, name)
+			}
+			body = datacenters
+		case r.URL.Path == "/v1/catalog/nodes":
+			body = catalog.nodes[dc]
+		case r.URL.Path == "/v1/catalog/services":
+			services := make(map[string][]string)
+			for service := range catalog.services[dc] {
+				services[service] = nil
+			}
+			body = services
+		case strings.HasPrefix(r.URL.Path, "/v1/catalog/service/"):
+			var instances []consulNode
+			for _, name := range catalog.services[dc][strings.TrimPrefix(r.URL.Path, "/v1/catalog/service/")] {
+				instances = append(instances, consulNode{Node: name, Datacenter: dc})
+			}
+			body = instances
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(standIn.Close)
+	return standIn
+}
+
+func (s *consulStandIn) requested() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func testConsulCatalog() consulCatalog {
+	return consulCatalog{
+		nodes: map[string][]consulNode{
+			"dc1": {
+				{Node: "web1", Address: "10.0.1.1", Datacenter: "dc1", Meta: map[string]string{"role": "web", "env": "prod"}},
+				{Node: "db1", Address: "10.0.1.2", Datacenter: "dc1"},
+			},
+			"dc2": {
+				{Node: "web2", Address: "10.0.2.1", Datacenter: "dc2", Meta: map[string]string{"env": "staging"}},
+			},
+		},
+		services: map[string]map[string][]string{
+			"dc1": {"web": {"web1", "web1"}, "postgres": {"db1"}},
+			"dc2": {"web": {"web2"}},
+		},
+		index: map[string]string{
+			"dc1/v1/catalog/nodes":    "10",
+			"dc1/v1/catalog/services": "11",
+			"dc2/v1/catalog/nodes":    "20",
+			"dc2/v1/catalog/services": "21",
+		},
+	}
+}
+
+func groupSummary(provider *ConsulProvider) (map[string][]string, error) {
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	summary := make(map[string][]string)
+	for _, group := range groups {
+		summary[group.Name] = []string{}
+		for _, host := range group.Hosts {
+			summary[group.Name] = append(summary[group.Name], host.Name)
+		}
+	}
+	return summary, nil
+}
+
+func TestConsulDatacenterFiltering(t *testing.T) {
+	standIn := newConsulStandIn(t, testConsulCatalog())
+
+	tests := []struct {
+		name        string
+		datacenters []string
+		want        map[string][]string
+	}{
+		{
+			name: "agent datacenter",
+			want: map[string][]string{"dc1": {"web1", "db1"}, "web": {"web1"}, "postgres": {"db1"}},
+		},
+		{
+			name:        "named datacenter",
+			datacenters: []string{"dc2"},
+			want:        map[string][]string{"dc2": {"web2"}, "web": {"web2"}},
+		},
+		{
+			name:        "every datacenter",
+			datacenters: []string{"*"},
+			want: map[string][]string{
+				"dc1": {"web1", "db1"}, "dc1/web": {"web1"}, "dc1/postgres": {"db1"},
+				"dc2": {"web2"}, "dc2/web": {"web2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := NewConsulProvider("consul", ConsulOptions{Address: standIn.URL, Token: "secret", Datacenters: test.datacenters})
+			got, err := groupSummary(provider)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("groups = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	for _, request := range standIn.requested() {
+		if strings.Contains(request, "dc=dc1") && strings.Contains(request, "dc=dc2") {
+			t.Errorf("request %s mixes datacenters", request)
+		}
+	}
+}
+
+func TestConsulNodeMetaTags(t *testing.T) {
+	standIn := newConsulStandIn(t, testConsulCatalog())
+
+	provider := NewConsulProvider("consul", ConsulOptions{Address: standIn.URL, Token: "secret", User: "ops", Port: 2222})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := groups[0].Hosts[0]
+	if host.Name != "web1" || host.Hostname != "10.0.1.1" || host.User != "ops" || host.Port != 2222 {
+		t.Errorf("host = %+v", host)
+	}
+	if want := []string{"dc=dc1", "env=prod", "role=web"}; !reflect.DeepEqual(host.Tags, want) {
+		t.Errorf("tags = %v, want %v", host.Tags, want)
+	}
+}
+
+func TestConsulRejectedToken(t *testing.T) {
+	standIn := newConsulStandIn(t, testConsulCatalog())
+
+	provider := NewConsulProvider("consul", ConsulOptions{Address: standIn.URL, Token: "wrong"})
+	_, err := provider.GetGroups(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("error = %v, want the 403 response", err)
+	}
+}
+
+func TestConsulVersion(t *testing.T) {
+	standIn := newConsulStandIn(t, testConsulCatalog())
+	provider := NewConsulProvider("consul", ConsulOptions{Address: standIn.URL, Token: "secret", Datacenters: []string{"dc1", "dc2"}, Wait: 2 * time.Second})
+
+	version, err := provider.Version(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "dc1/v1/catalog/nodes=10,dc1/v1/catalog/services=11,dc2/v1/catalog/nodes=20,dc2/v1/catalog/services=21"
+	if version != want {
+		t.Errorf("version = %q, want %q", version, want)
+	}
+	for _, request := range standIn.requested() {
+		if strings.Contains(request, "index=") {
+			t.Errorf("request %s is a blocking query without a previous index", request)
+		}
+	}
+
+	standIn.mu.Lock()
+	standIn.requests = nil
+	standIn.mu.Unlock()
+
+	if _, err := provider.Version(context.Background(), version); err != nil {
+		t.Fatal(err)
+	}
+
+	var blocking []string
+	for _, request := range standIn.requested() {
+		if strings.Contains(request, "index=") {
+			blocking = append(blocking, request)
+		}
+	}
+	if len(blocking) != 1 || !strings.Contains(blocking[0], "index=10") || !strings.Contains(blocking[0], "wait=2s") {
+		t.Errorf("blocking requests = %v, want one on the first endpoint with index=10 and wait=2s", blocking)
+	}
+}
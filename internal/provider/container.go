@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type ContainerProvider struct {
+	name     string
+	runtime  string
+	options  ContainerOptions
+	warnings []string
+}
+
+type ContainerOptions struct {
+	All     bool
+	GroupBy string
+	User    string
+}
+
+type containerInfo struct {
+	id      string
+	name    string
+	image   string
+	state   string
+	labels  map[string]string
+	running bool
+}
+
+type dockerPSEntry struct {
+	ID     string `json:"ID"`
+	Names  string `json:"Names"`
+	Image  string `json:"Image"`
+	State  string `json:"State"`
+	Labels string `json:"Labels"`
+}
+
+type podmanPSEntry struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Image  string            `json:"Image"`
+	State  string            `json:"State"`
+	Labels map[string]string `json:"Labels"`
+}
+
+var composeProjectLabels = []string{"com.docker.compose.project", "io.podman.compose.project"}
+
+func NewContainerProvider(name, runtime string, options ContainerOptions) *ContainerProvider {
+	if options.GroupBy == "" {
+		options.GroupBy = "compose"
+	}
+
+	return &ContainerProvider{
+		name:    name,
+		runtime: runtime,
+		options: options,
+	}
+}
+
+func (p *ContainerProvider) Name() string {
+	return p.name
+}
+
+func (p *ContainerProvider) Warnings() []string {
+	return p.warnings
+}
+
+func (p *ContainerProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	p.warnings = nil
+
+	args := []string{"ps", "--no-trunc"}
+	if p.options.All {
+		args = append(args, "--all")
+	}
+	if p.runtime == "podman" {
+		args = append(args, "--format", "json")
+	} else {
+		args = append(args, "--format", "{{json .}}")
+	}
+
+	cmd := exec.CommandContext(ctx, p.runtime, args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			p.warnings = append(p.warnings, fmt.Sprintf("%s ps failed: %s", p.runtime, strings.TrimSpace(string(exitErr.Stderr))))
+		} else {
+			p.warnings = append(p.warnings, fmt.Sprintf("failed to run %s: %v", p.runtime, err))
+		}
+		return []*types.Group{}, nil
+	}
+
+	var containers []containerInfo
+	if p.runtime == "podman" {
+		containers, err = parsePodmanPS(output)
+	} else {
+		containers, err = parseDockerPS(output)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s ps output: %w", p.runtime, err)
+	}
+
+	groups := []*types.Group{}
+	groupsByName := make(map[string]*types.Group)
+
+	for _, container := range containers {
+		if container.name == "" {
+			continue
+		}
+
+		host := &types.Host{
+			Name:      container.name,
+			Hostname:  container.name,
+			User:      p.options.User,
+			Transport: p.runtime,
+			Tags:      []string{"image=" + container.image, "container=" + shortContainerID(container.id), "state=" + container.state},
+			Inactive:  !container.running,
+		}
+		if service := container.labels["com.docker.compose.service"]; service != "" {
+			host.Tags = append(host.Tags, "service="+service)
+		}
+
+		groupName := p.groupName(container)
+		group, exists := groupsByName[groupName]
+		if !exists {
+			group = &types.Group{Name: groupName, Hosts: []*types.Host{}}
+			groupsByName[groupName] = group
+			groups = append(groups, group)
+		}
+		group.Hosts = append(group.Hosts, host)
+	}
+
+	return groups, nil
+}
+
+func (p *ContainerProvider) groupName(container containerInfo) string {
+	switch p.options.GroupBy {
+	case "image":
+		return container.image
+	case "none":
+		return p.runtime
+	default:
+		for _, label := range composeProjectLabels {
+			if project := container.labels[label]; project != "" {
+				return project
+			}
+		}
+		return "standalone"
+	}
+}
+
+func parseDockerPS(output []byte) ([]containerInfo, error) {
+	var containers []containerInfo
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry dockerPSEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, err
+		}
+
+		labels := make(map[string]string)
+		for _, label := range splitNonEmpty(entry.Labels, ",") {
+			if key, value, ok := strings.Cut(label, "="); ok {
+				labels[key] = value
+			}
+		}
+
+		containers = append(containers, containerInfo{
+			id:      entry.ID,
+			name:    strings.Split(entry.Names, ",")[0],
+			image:   entry.Image,
+			state:   entry.State,
+			labels:  labels,
+			running: entry.State == "running",
+		})
+	}
+
+	return containers, scanner.Err()
+}
+
+func parsePodmanPS(output []byte) ([]containerInfo, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var entries []podmanPSEntry
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, err
+	}
+
+	var containers []containerInfo
+	for _, entry := range entries {
+		container := containerInfo{
+			id:      entry.ID,
+			image:   entry.Image,
+			state:   entry.State,
+			labels:  entry.Labels,
+			running: entry.State == "running",
+		}
+		if len(entry.Names) > 0 {
+			container.name = entry.Names[0]
+		}
+		containers = append(containers, container)
+	}
+
+	return containers, nil
+}
+
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const dockerPSOutput = `{"Command":"\"docker-entrypoint.s…\"","ID":"3f4e1c2a9b8d7e6f5a4b3c2d1e0f","Image":"postgres:16","Labels":"com.docker.compose.project=shop,com.docker.compose.service=db,maintainer=ops","Names":"shop-db-1","State":"running","Status":"Up 2 hours"}
+{"Command":"\"nginx -g 'daemon of…\"","ID":"9a8b7c6d5e4f3a2b1c0d9e8f7a6b","Image":"nginx:1.25","Labels":"com.docker.compose.project=shop,com.docker.compose.service=web","Names":"shop-web-1","State":"exited","Status":"Exited (0) 3 minutes ago"}
+{"Command":"\"sleep infinity\"","ID":"0123456789abcdef","Image":"alpine:3.19","Labels":"","Names":"scratch,scratch-alias","State":"running","Status":"Up 5 seconds"}
+`
+
+const podmanPSOutput = `[
+  {
+    "Id": "c0ffee00c0ffee00c0ffee00",
+    "Image": "docker.io/library/redis:7",
+    "Names": ["cache_redis_1"],
+    "State": "running",
+    "Labels": {"io.podman.compose.project": "cache", "com.docker.compose.service": "redis"}
+  },
+  {
+    "Id": "deadbeefdeadbeefdeadbeef",
+    "Image": "quay.io/fedora/fedora:40",
+    "Names": ["toolbox"],
+    "State": "exited",
+    "Labels": null
+  }
+]
+`
+
+func TestParseDockerPS(t *testing.T) {
+	containers, err := parseDockerPS([]byte(dockerPSOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []containerInfo{
+		{
+			id:      "3f4e1c2a9b8d7e6f5a4b3c2d1e0f",
+			name:    "shop-db-1",
+			image:   "postgres:16",
+			state:   "running",
+			labels:  map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.service": "db", "maintainer": "ops"},
+			running: true,
+		},
+		{
+			id:     "9a8b7c6d5e4f3a2b1c0d9e8f7a6b",
+			name:   "shop-web-1",
+			image:  "nginx:1.25",
+			state:  "exited",
+			labels: map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.service": "web"},
+		},
+		{
+			id:      "0123456789abcdef",
+			name:    "scratch",
+			image:   "alpine:3.19",
+			state:   "running",
+			labels:  map[string]string{},
+			running: true,
+		},
+	}
+	if !reflect.DeepEqual(containers, want) {
+		t.Errorf("containers = %+v, want %+v", containers, want)
+	}
+}
+
+func TestParsePodmanPS(t *testing.T) {
+	containers, err := parsePodmanPS([]byte(podmanPSOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []containerInfo{
+		{
+			id:      "c0ffee00c0ffee00c0ffee00",
+			name:    "cache_redis_1",
+			image:   "docker.io/library/redis:7",
+			state:   "running",
+			labels:  map[string]string{"io.podman.compose.project": "cache", "com.docker.compose.service": "redis"},
+			running: true,
+		},
+		{
+			id:    "deadbeefdeadbeefdeadbeef",
+			name:  "toolbox",
+			image: "quay.io/fedora/fedora:40",
+			state: "exited",
+		},
+	}
+	if !reflect.DeepEqual(containers, want) {
+		t.Errorf("containers = %+v, want %+v", containers, want)
+	}
+
+	for _, output := range []string{"", "[]\n"} {
+		if containers, err := parsePodmanPS([]byte(output)); err != nil || len(containers) != 0 {
+			t.Errorf("parsePodmanPS(%q) = %v, %v, want no containers", output, containers, err)
+		}
+	}
+}
+
+func TestContainerGrouping(t *testing.T) {
+	tests := []struct {
+		name    string
+		runtime string
+		output  string
+		groupBy string
+		want    map[string][]string
+	}{
+		{
+			name:    "docker compose projects",
+			runtime: "docker",
+			output:  dockerPSOutput,
+			want:    map[string][]string{"shop": {"shop-db-1", "shop-web-1"}, "standalone": {"scratch"}},
+		},
+		{
+			name:    "docker by image",
+			runtime: "docker",
+			output:  dockerPSOutput,
+			groupBy: "image",
+			want:    map[string][]string{"postgres:16": {"shop-db-1"}, "nginx:1.25": {"shop-web-1"}, "alpine:3.19": {"scratch"}},
+		},
+		{
+			name:    "podman compose projects",
+			runtime: "podman",
+			output:  podmanPSOutput,
+			want:    map[string][]string{"cache": {"cache_redis_1"}, "standalone": {"toolbox"}},
+		},
+		{
+			name:    "podman ungrouped",
+			runtime: "podman",
+			output:  podmanPSOutput,
+			groupBy: "none",
+			want:    map[string][]string{"podman": {"cache_redis_1", "toolbox"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeCommand(t, test.runtime, "cat <<'PS'\n"+test.output+"PS\n")
+
+			provider := NewContainerProvider("containers", test.runtime, ContainerOptions{All: true, GroupBy: test.groupBy})
+			groups, err := provider.GetGroups(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(map[string][]string)
+			for _, group := range groups {
+				for _, host := range group.Hosts {
+					got[group.Name] = append(got[group.Name], host.Name)
+				}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("groups = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestContainerHosts(t *testing.T) {
+	fakeCommand(t, "docker", "cat <<'PS'\n"+dockerPSOutput+"PS\n")
+
+	provider := NewContainerProvider("containers", "docker", ContainerOptions{All: true, User: "app"})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	web := groups[0].Hosts[1]
+	if web.Name != "shop-web-1" || web.Transport != "docker" || web.User != "app" || !web.Inactive {
+		t.Errorf("host = %+v", web)
+	}
+	if want := []string{"image=nginx:1.25", "container=9a8b7c6d5e4f", "state=exited", "service=web"}; !reflect.DeepEqual(web.Tags, want) {
+		t.Errorf("tags = %v, want %v", web.Tags, want)
+	}
+}
+
+func TestContainerProviderWithoutContainers(t *testing.T) {
+	tests := []struct {
+		name     string
+		runtime  string
+		script   string
+		warnings int
+	}{
+		{"docker without containers", "docker", "exit 0\n", 0},
+		{"podman without containers", "podman", "echo '[]'\n", 0},
+		{"daemon not running", "docker", "echo 'Cannot connect to the Docker daemon at unix:///var/run/docker.sock.' >&2\nexit 1\n", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeCommand(t, test.runtime, test.script)
+
+			provider := NewContainerProvider("containers", test.runtime, ContainerOptions{})
+			groups, err := provider.GetGroups(context.Background())
+			if err != nil {
+				t.Fatalf("provider failed: %v", err)
+			}
+			if len(groups) != 0 {
+				t.Errorf("groups = %+v, want none", groups)
+			}
+			if len(provider.Warnings()) != test.warnings {
+				t.Errorf("warnings = %q, want %d", provider.Warnings(), test.warnings)
+			}
+			if test.warnings > 0 && !strings.Contains(provider.Warnings()[0], "Cannot connect") {
+				t.Errorf("warning %q does not include the daemon error", provider.Warnings()[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type CSVProvider struct {
+	name     string
+	filepath string
+	options  CSVOptions
+}
+
+type CSVOptions struct {
+	Delimiter     string
+	ListSeparator string
+	HasHeader     bool
+	DefaultGroup  string
+	Columns       map[string]interface{}
+}
+
+var csvFields = []string{"name", "hostname", "port", "user", "group", "tags"}
+
+func NewCSVProvider(name, filepath string, options CSVOptions) *CSVProvider {
+	if options.Delimiter == "" {
+		options.Delimiter = ","
+	}
+	if options.ListSeparator == "" {
+		options.ListSeparator = ";"
+		if options.Delimiter == ";" {
+			options.ListSeparator = "|"
+		}
+	}
+
+	return &CSVProvider{
+		name:     name,
+		filepath: filepath,
+		options:  options,
+	}
+}
+
+func (p *CSVProvider) Name() string {
+	return p.name
+}
+
+func (p *CSVProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	file, err := os.Open(p.filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV file %s: %w", p.filepath, err)
+	}
+	defer file.Close()
+
+	delimiter, size := utf8.DecodeRuneInString(p.options.Delimiter)
+	if delimiter == utf8.RuneError || size != len(p.options.Delimiter) {
+		return nil, fmt.Errorf("CSV delimiter must be a single character, got %q", p.options.Delimiter)
+	}
+
+	reader := csv.NewReader(file)
+	reader.Comma = delimiter
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV file %s: %w", p.filepath, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	var header []string
+	if p.options.HasHeader && len(records) > 0 {
+		header = records[0]
+		records, lines = records[1:], lines[1:]
+	}
+
+	indexes, err := p.resolveColumns(header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid column mapping for CSV file %s: %w", p.filepath, err)
+	}
+
+	defaultGroup := p.options.DefaultGroup
+	if defaultGroup == "" {
+		defaultGroup = strings.TrimSuffix(filepath.Base(p.filepath), filepath.Ext(p.filepath))
+	}
+
+	var groups []*types.Group
+	groupsByName := make(map[string]*types.Group)
+
+	for i, record := range records {
+		field := func(name string) string {
+			index, ok := indexes[name]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		host := &types.Host{
+			Name:     field("name"),
+			Hostname: field("hostname"),
+			User:     field("user"),
+			Tags:     splitNonEmpty(field("tags"), p.options.ListSeparator),
+		}
+		if host.Name == "" {
+			host.Name = host.Hostname
+		}
+		if host.Hostname == "" {
+			host.Hostname = host.Name
+		}
+		if host.Name == "" {
+			continue
+		}
+
+		if port := field("port"); port != "" {
+			value, err := strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port %q in CSV file %s line %d", port, p.filepath, lines[i])
+			}
+			host.Port = value
+		}
+
+		groupNames := splitNonEmpty(field("group"), p.options.ListSeparator)
+		if len(groupNames) == 0 {
+			groupNames = []string{defaultGroup}
+		}
+
+		for _, groupName := range groupNames {
+			group, exists := groupsByName[groupName]
+			if !exists {
+				group = &types.Group{Name: groupName, Hosts: []*types.Host{}}
+				groupsByName[groupName] = group
+				groups = append(groups, group)
+			}
+			group.Hosts = append(group.Hosts, host)
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no hosts found in CSV file %s", p.filepath)
+	}
+
+	return groups, nil
+}
+
+func (p *CSVProvider) resolveColumns(header []string) (map[string]int, error) {
+	indexes := make(map[string]int)
+
+	headerIndex := func(column string) (int, bool) {
+		for i, title := range header {
+			if strings.EqualFold(strings.TrimSpace(title), column) {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	for _, field := range csvFields {
+		mapping, mapped := p.options.Columns[field]
+		if !mapped {
+			if index, ok := headerIndex(field); ok {
+				indexes[field] = index
+			} else if field == "group" {
+				if index, ok := headerIndex("groups"); ok {
+					indexes[field] = index
+				}
+			}
+			continue
+		}
+
+		switch v := mapping.(type) {
+		case float64:
+			if v < 1 {
+				return nil, fmt.Errorf("column index for %s must be 1 or greater", field)
+			}
+			indexes[field] = int(v) - 1
+		case string:
+			index, ok := headerIndex(v)
+			if !ok {
+				return nil, fmt.Errorf("column %q for %s not found in header", v, field)
+			}
+			indexes[field] = index
+		default:
+			return nil, fmt.Errorf("column mapping for %s must be a header name or a 1-based index", field)
+		}
+	}
+
+	if _, ok := indexes["name"]; !ok {
+		if _, ok := indexes["hostname"]; !ok {
+			return nil, fmt.Errorf("a name or hostname column is required")
+		}
+	}
+
+	return indexes, nil
+}
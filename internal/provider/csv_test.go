@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "hosts.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCSVListSeparator(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter string
+		content   string
+	}{
+		{"comma delimiter", "", "name,group,tags\nweb1,prod;web,linux;nginx\n"},
+		{"semicolon delimiter", ";", "name;group;tags\nweb1;prod|web;linux|nginx\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := NewCSVProvider("csv", writeCSV(t, test.content), CSVOptions{Delimiter: test.delimiter, HasHeader: true})
+			groups, err := provider.GetGroups(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for _, group := range groups {
+				names = append(names, group.Name)
+			}
+			if want := []string{"prod", "web"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("groups = %v, want %v", names, want)
+			}
+			if want := []string{"linux", "nginx"}; !reflect.DeepEqual(groups[0].Hosts[0].Tags, want) {
+				t.Errorf("tags = %v, want %v", groups[0].Hosts[0].Tags, want)
+			}
+		})
+	}
+}
+
+func TestCSVErrorLineNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		hasHeader bool
+		content   string
+		want      string
+	}{
+		{"without header", false, "web1,22\nweb2,abc\n", "line 2"},
+		{"with header", true, "name,port\nweb1,22\nweb2,abc\n", "line 3"},
+		{"with comments", true, "name,port\n# staging\nweb1,22\n\nweb2,abc\n", "line 5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := CSVOptions{HasHeader: test.hasHeader}
+			if !test.hasHeader {
+				options.Columns = map[string]interface{}{"name": float64(1), "port": float64(2)}
+			}
+
+			_, err := NewCSVProvider("csv", writeCSV(t, test.content), options).GetGroups(context.Background())
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("error = %v, want it to mention %s", err, test.want)
+			}
+		})
+	}
+}
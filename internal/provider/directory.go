@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type DirectoryProvider struct {
+	name      string
+	directory string
+	warnings  []string
+}
+
+func NewDirectoryProvider(name, directory string) *DirectoryProvider {
+	return &DirectoryProvider{
+		name:      name,
+		directory: directory,
+	}
+}
+
+func (p *DirectoryProvider) Name() string {
+	return p.name
+}
+
+func (p *DirectoryProvider) Warnings() []string {
+	return p.warnings
+}
+
+func (p *DirectoryProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	p.warnings = nil
+
+	info, err := os.Stat(p.directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", p.directory, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", p.directory)
+	}
+
+	var failures []string
+	groups, err := p.loadDirectory(ctx, p.directory, &failures)
+	if err != nil {
+		return nil, err
+	}
+
+	totalHosts := 0
+	for _, group := range groups {
+		totalHosts += len(group.AllHosts())
+	}
+
+	if totalHosts == 0 {
+		if len(failures) > 0 {
+			return nil, fmt.Errorf("no hosts found in directory %s: %s", p.directory, strings.Join(failures, "; "))
+		}
+		return nil, fmt.Errorf("no hosts found in directory %s", p.directory)
+	}
+
+	p.warnings = failures
+	return groups, nil
+}
+
+func (p *DirectoryProvider) loadDirectory(ctx context.Context, directory string, failures *[]string) ([]*types.Group, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", directory, err)
+	}
+
+	var groups []*types.Group
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(directory, entry.Name())
+
+		if entry.IsDir() {
+			subGroups, err := p.loadDirectory(ctx, path, failures)
+			if err != nil {
+				*failures = append(*failures, err.Error())
+				continue
+			}
+			if len(subGroups) == 0 {
+				continue
+			}
+			groups = mergeGroups(groups, []*types.Group{{
+				Name:      entry.Name(),
+				Hosts:     []*types.Host{},
+				SubGroups: subGroups,
+			}})
+			continue
+		}
+
+		fileProvider := directoryFileProvider(p.name, path)
+		if fileProvider == nil {
+			continue
+		}
+
+		fileGroups, err := fileProvider.GetGroups(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			*failures = append(*failures, err.Error())
+			continue
+		}
+		groups = mergeGroups(groups, fileGroups)
+	}
+
+	return groups, nil
+}
+
+func directoryFileProvider(name, path string) Provider {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return NewJSONProvider(name, path)
+	case ".yml", ".yaml":
+		return NewYAMLProvider(name, path)
+	case ".toml":
+		return NewTOMLProvider(name, path)
+	case ".csv":
+		return NewCSVProvider(name, path, CSVOptions{HasHeader: true})
+	default:
+		return nil
+	}
+}
+
+func mergeGroups(groups, additions []*types.Group) []*types.Group {
+	for _, addition := range additions {
+		merged := false
+		for _, group := range groups {
+			if group.Name == addition.Name {
+				group.Hosts = append(group.Hosts, addition.Hosts...)
+				group.SubGroups = mergeGroups(group.SubGroups, addition.SubGroups)
+				if group.Description == "" {
+					group.Description = addition.Description
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			groups = append(groups, addition)
+		}
+	}
+	return groups
+}
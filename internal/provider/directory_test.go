@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDirectorySkipsBrokenFiles(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"hosts.json":         `[{"name": "web", "hosts": [{"name": "web1", "hostname": "10.0.0.1"}]}]`,
+		"package.json":       `{"name": "frontend", "version": "1.0.0"}`,
+		"empty.csv":          "",
+		"broken.yaml":        "groups: [",
+		"team/db.json":       `[{"name": "db", "hosts": [{"name": "db1", "hostname": "10.0.0.2"}]}]`,
+		"team/notes.json":    `not json`,
+		"team/ignored.txt":   "ignored",
+		"other/nothing.toml": "",
+	})
+
+	provider := NewDirectoryProvider("tree", dir)
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, group := range groups {
+		for _, host := range group.AllHosts() {
+			names = append(names, host.Name)
+		}
+	}
+	if strings.Join(names, ",") != "web1,db1" && strings.Join(names, ",") != "db1,web1" {
+		t.Errorf("hosts = %v, want web1 and db1", names)
+	}
+
+	warnings := strings.Join(provider.Warnings(), "\n")
+	for _, file := range []string{"package.json", "empty.csv", "broken.yaml", "notes.json", "nothing.toml"} {
+		if !strings.Contains(warnings, file) {
+			t.Errorf("warnings do not mention %s:\n%s", file, warnings)
+		}
+	}
+	if strings.Contains(warnings, "hosts.json") || strings.Contains(warnings, "db.json") {
+		t.Errorf("warnings mention a file that loaded:\n%s", warnings)
+	}
+}
+
+func TestDirectoryWarnsAboutOneBrokenFile(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"good.yaml": "- name: web\n  hosts:\n    - name: web1\n      hostname: 10.0.0.1\n",
+		"bad.yaml":  "- name: db\n  hosts:\n    - name: db1\n      hostname: [\n",
+	})
+
+	provider := NewDirectoryProvider("tree", dir)
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || len(groups[0].Hosts) != 1 || groups[0].Hosts[0].Name != "web1" {
+		t.Errorf("groups = %+v, want the hosts from good.yaml", groups)
+	}
+	if warnings := provider.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "bad.yaml") {
+		t.Errorf("warnings = %q, want one naming bad.yaml", warnings)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("- name: db\n  hosts:\n    - name: db1\n      hostname: 10.0.0.2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	groups, err = provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 {
+		t.Errorf("groups = %+v, want web and db after fixing the file", groups)
+	}
+	if warnings := provider.Warnings(); len(warnings) != 0 {
+		t.Errorf("warnings = %q after fixing the file, want none", warnings)
+	}
+}
+
+func TestDirectoryReportsFailuresWithoutHosts(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"package.json": `{"name": "frontend"}`,
+		"empty.csv":    "",
+	})
+
+	_, err := NewDirectoryProvider("tree", dir).GetGroups(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "package.json") {
+		t.Fatalf("error = %v, want it to name the failing files", err)
+	}
+}
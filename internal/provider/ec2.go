@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type EC2Provider struct {
+	name    string
+	options CloudOptions
+}
+
+type ec2Output struct {
+	Reservations []struct {
+		Instances []ec2Instance `json:"Instances"`
+	} `json:"Reservations"`
+}
+
+type ec2Instance struct {
+	InstanceID       string `json:"InstanceId"`
+	PrivateIPAddress string `json:"PrivateIpAddress"`
+	PublicIPAddress  string `json:"PublicIpAddress"`
+	VpcID            string `json:"VpcId"`
+	State            struct {
+		Name string `json:"Name"`
+	} `json:"State"`
+	Placement struct {
+		AvailabilityZone string `json:"AvailabilityZone"`
+	} `json:"Placement"`
+	Tags []struct {
+		Key   string `json:"Key"`
+		Value string `json:"Value"`
+	} `json:"Tags"`
+}
+
+func NewEC2Provider(name string, options CloudOptions) *EC2Provider {
+	if options.File == "" && len(options.Command) == 0 {
+		options.Command = []string{"aws", "ec2", "describe-instances", "--output", "json"}
+	}
+
+	return &EC2Provider{
+		name:    name,
+		options: options,
+	}
+}
+
+func (p *EC2Provider) Name() string {
+	return p.name
+}
+
+func (p *EC2Provider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	data, err := loadCloudOutput(ctx, p.options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load EC2 instances: %w", err)
+	}
+
+	var output ec2Output
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse EC2 describe-instances output from %s: %w", p.options.source(), err)
+	}
+
+	var instances []cloudInstance
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.State.Name == "terminated" || instance.State.Name == "shutting-down" {
+				continue
+			}
+
+			labels := make(map[string]string)
+			for _, tag := range instance.Tags {
+				labels[tag.Key] = tag.Value
+			}
+
+			name := labels["Name"]
+			if name == "" {
+				name = instance.InstanceID
+			}
+
+			tags := []string{"instance=" + instance.InstanceID}
+			for key, value := range labels {
+				tags = append(tags, key+"="+value)
+			}
+			sort.Strings(tags[1:])
+
+			instances = append(instances, cloudInstance{
+				name:      name,
+				privateIP: instance.PrivateIPAddress,
+				publicIP:  instance.PublicIPAddress,
+				running:   instance.State.Name == "running",
+				state:     instance.State.Name,
+				groupNames: map[string]string{
+					"vpc": instance.VpcID,
+					"az":  instance.Placement.AvailabilityZone,
+				},
+				labels: labels,
+				tags:   tags,
+			})
+		}
+	}
+
+	groups := buildCloudGroups(instances, p.options)
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no EC2 instances with an IP address found in %s", p.options.source())
+	}
+
+	return groups, nil
+}
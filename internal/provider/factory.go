@@ -2,12 +2,17 @@ package provider
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/tech-arch1tect/lssh/internal/cache"
+	"github.com/tech-arch1tect/lssh/internal/ssh"
 )
 
 type CacheConfig interface {
 	IsCacheEnabled() bool
+	IsOffline() bool
 }
 
 func NewProvider(config Config, appConfig CacheConfig) (Provider, error) {
@@ -22,6 +27,20 @@ func NewProvider(config Config, appConfig CacheConfig) (Provider, error) {
 		}
 		filepath = fp
 		baseProvider = NewJSONProvider(config.Name, filepath)
+	case "yaml":
+		fp, ok := config.Config["file"].(string)
+		if !ok {
+			return nil, fmt.Errorf("yaml provider requires 'file' config parameter")
+		}
+		filepath = fp
+		baseProvider = NewYAMLProvider(config.Name, filepath)
+	case "toml":
+		fp, ok := config.Config["file"].(string)
+		if !ok {
+			return nil, fmt.Errorf("toml provider requires 'file' config parameter")
+		}
+		filepath = fp
+		baseProvider = NewTOMLProvider(config.Name, filepath)
 	case "ansible":
 		fp, ok := config.Config["file"].(string)
 		if !ok {
@@ -29,13 +48,296 @@ func NewProvider(config Config, appConfig CacheConfig) (Provider, error) {
 		}
 		filepath = fp
 		baseProvider = NewAnsibleProvider(config.Name, filepath)
+	case "known_hosts":
+		files := configStringList(config.Config, "files")
+		if fp, ok := config.Config["file"].(string); ok {
+			files = append(files, fp)
+		}
+		if len(files) == 0 {
+			files = ssh.DefaultKnownHostsFiles()
+		}
+		filepath = strings.Join(files, ",")
+		baseProvider = NewKnownHostsProvider(config.Name, files)
+	case "hostsfile":
+		fp, ok := config.Config["file"].(string)
+		if !ok {
+			fp = "/etc/hosts"
+		}
+		filepath = fp
+		defaultGroup, _ := config.Config["group"].(string)
+		includeLoopback, _ := config.Config["include_loopback"].(bool)
+		baseProvider = NewHostsFileProvider(config.Name, filepath, defaultGroup, includeLoopback)
+	case "csv":
+		fp, ok := config.Config["file"].(string)
+		if !ok {
+			return nil, fmt.Errorf("csv provider requires 'file' config parameter")
+		}
+		filepath = fp
+		options := CSVOptions{HasHeader: true}
+		options.Delimiter, _ = config.Config["delimiter"].(string)
+		options.ListSeparator, _ = config.Config["list_separator"].(string)
+		options.DefaultGroup, _ = config.Config["default_group"].(string)
+		options.Columns, _ = config.Config["columns"].(map[string]interface{})
+		if hasHeader, ok := config.Config["has_header"].(bool); ok {
+			options.HasHeader = hasHeader
+		}
+		baseProvider = NewCSVProvider(config.Name, filepath, options)
+	case "directory":
+		dir, ok := config.Config["directory"].(string)
+		if !ok {
+			return nil, fmt.Errorf("directory provider requires 'directory' config parameter")
+		}
+		filepath = dir
+		baseProvider = NewDirectoryProvider(config.Name, filepath)
+	case "terraform":
+		fp, ok := config.Config["file"].(string)
+		if !ok {
+			fp = "terraform.tfstate"
+		}
+		filepath = fp
+		options := TerraformOptions{
+			ResourceTypes:     configStringList(config.Config, "resource_types"),
+			AddressAttributes: configStringList(config.Config, "address_attributes"),
+			NameAttributes:    configStringList(config.Config, "name_attributes"),
+		}
+		options.GroupBy, _ = config.Config["group_by"].(string)
+		if options.GroupBy != "" && options.GroupBy != "module" && options.GroupBy != "type" && !strings.HasPrefix(options.GroupBy, "tag:") {
+			return nil, fmt.Errorf("terraform provider 'group_by' must be module, type or tag:<key>, got %q", options.GroupBy)
+		}
+		options.User, _ = config.Config["user"].(string)
+		if port, ok := config.Config["port"].(float64); ok {
+			options.Port = int(port)
+		}
+		baseProvider = NewTerraformProvider(config.Name, filepath, options)
+	case "ec2":
+		options, err := cloudOptionsFromConfig(config.Config)
+		if err != nil {
+			return nil, fmt.Errorf("ec2 provider: %w", err)
+		}
+		if options.File == "" && len(options.Command) == 0 {
+			options.Command = []string{"aws", "ec2", "describe-instances", "--output", "json"}
+			if profile, ok := config.Config["profile"].(string); ok {
+				options.Command = append(options.Command, "--profile", profile)
+			}
+			if region, ok := config.Config["region"].(string); ok {
+				options.Command = append(options.Command, "--region", region)
+			}
+		}
+		filepath = options.source()
+		baseProvider = NewEC2Provider(config.Name, options)
+	case "gce":
+		options, err := cloudOptionsFromConfig(config.Config)
+		if err != nil {
+			return nil, fmt.Errorf("gce provider: %w", err)
+		}
+		if options.File == "" && len(options.Command) == 0 {
+			options.Command = []string{"gcloud", "compute", "instances", "list", "--format=json"}
+			if project, ok := config.Config["project"].(string); ok {
+				options.Command = append(options.Command, "--project", project)
+			}
+		}
+		filepath = options.source()
+		baseProvider = NewGCEProvider(config.Name, options)
+	case "consul":
+		options := ConsulOptions{Datacenters: configStringList(config.Config, "datacenters")}
+		options.Address, _ = config.Config["address"].(string)
+		if options.Address == "" {
+			options.Address = os.Getenv("CONSUL_HTTP_ADDR")
+			if options.Address != "" && !strings.Contains(options.Address, "://") && os.Getenv("CONSUL_HTTP_SSL") == "true" {
+				options.Address = "https://" + options.Address
+			}
+		}
+		if dc, ok := config.Config["datacenter"].(string); ok {
+			options.Datacenters = append(options.Datacenters, dc)
+		}
+		if wait, ok := config.Config["wait"].(string); ok {
+			duration, err := time.ParseDuration(wait)
+			if err != nil {
+				return nil, fmt.Errorf("consul provider has invalid 'wait' duration %q", wait)
+			}
+			options.Wait = duration
+		}
+		token, _ := config.Config["token"].(string)
+		tokenFile, _ := config.Config["token_file"].(string)
+		token, err := resolveToken(token, tokenFile, "CONSUL_HTTP_TOKEN", "CONSUL_HTTP_TOKEN_FILE")
+		if err != nil {
+			return nil, err
+		}
+		options.Token = token
+		options.User, _ = config.Config["user"].(string)
+		if port, ok := config.Config["port"].(float64); ok {
+			options.Port = int(port)
+		}
+		consulProvider := NewConsulProvider(config.Name, options)
+		filepath = consulProvider.source()
+		baseProvider = consulProvider
+	case "netbox":
+		options := NetBoxOptions{
+			Include: configStringList(config.Config, "include"),
+			Filters: make(map[string][]string),
+		}
+		options.URL, _ = config.Config["url"].(string)
+		if options.URL == "" {
+			options.URL = os.Getenv("NETBOX_URL")
+		}
+		if options.URL == "" {
+			return nil, fmt.Errorf("netbox provider requires 'url' config parameter")
+		}
+		for _, kind := range options.Include {
+			if _, ok := netboxEndpoints[kind]; !ok {
+				return nil, fmt.Errorf("netbox provider 'include' entries must be devices or virtual_machines, got %q", kind)
+			}
+		}
+		if filters, ok := config.Config["filters"].(map[string]interface{}); ok {
+			for key := range filters {
+				options.Filters[key] = configStringList(filters, key)
+			}
+		}
+		options.Address, _ = config.Config["address"].(string)
+		if options.Address != "" && options.Address != "primary" && options.Address != "primary4" && options.Address != "primary6" {
+			return nil, fmt.Errorf("netbox provider 'address' must be primary, primary4 or primary6, got %q", options.Address)
+		}
+		options.GroupBy, _ = config.Config["group_by"].(string)
+		if options.GroupBy != "" && options.GroupBy != "site" && options.GroupBy != "role" && options.GroupBy != "tenant" {
+			return nil, fmt.Errorf("netbox provider 'group_by' must be site, role or tenant, got %q", options.GroupBy)
+		}
+		if pageSize, ok := config.Config["page_size"].(float64); ok {
+			options.PageSize = int(pageSize)
+		}
+		token, _ := config.Config["token"].(string)
+		tokenFile, _ := config.Config["token_file"].(string)
+		token, err := resolveToken(token, tokenFile, "NETBOX_TOKEN", "NETBOX_TOKEN_FILE")
+		if err != nil {
+			return nil, err
+		}
+		options.Token = token
+		options.User, _ = config.Config["user"].(string)
+		if port, ok := config.Config["port"].(float64); ok {
+			options.Port = int(port)
+		}
+		netboxProvider := NewNetBoxProvider(config.Name, options)
+		filepath = netboxProvider.source()
+		baseProvider = netboxProvider
+	case "file_sd":
+		files := configStringList(config.Config, "files")
+		if fp, ok := config.Config["file"].(string); ok {
+			files = append(files, fp)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("file_sd provider requires 'file' or 'files' config parameter")
+		}
+		filepath = strings.Join(files, ",")
+		options := FileSDOptions{}
+		options.GroupBy, _ = config.Config["group_by"].(string)
+		options.NameLabel, _ = config.Config["name_label"].(string)
+		options.User, _ = config.Config["user"].(string)
+		if port, ok := config.Config["port"].(float64); ok {
+			options.Port = int(port)
+		}
+		baseProvider = NewFileSDProvider(config.Name, files, options)
+	case "vagrant":
+		directories := configStringList(config.Config, "directories")
+		if dir, ok := config.Config["directory"].(string); ok {
+			directories = append(directories, dir)
+		}
+		if len(directories) == 0 {
+			return nil, fmt.Errorf("vagrant provider requires 'directories' config parameter")
+		}
+		filepath = strings.Join(directories, ",")
+		baseProvider = NewVagrantProvider(config.Name, directories)
+	case "libvirt":
+		options := LibvirtOptions{}
+		options.URI, _ = config.Config["uri"].(string)
+		options.Source, _ = config.Config["source"].(string)
+		if options.Source != "" && options.Source != "lease" && options.Source != "agent" && options.Source != "arp" {
+			return nil, fmt.Errorf("libvirt provider 'source' must be lease, agent or arp, got %q", options.Source)
+		}
+		options.Group, _ = config.Config["group"].(string)
+		options.User, _ = config.Config["user"].(string)
+		options.IdentityFile, _ = config.Config["identity_file"].(string)
+		if port, ok := config.Config["port"].(float64); ok {
+			options.Port = int(port)
+		}
+		filepath = options.URI
+		baseProvider = NewLibvirtProvider(config.Name, options)
+	case "docker", "podman":
+		options := ContainerOptions{}
+		options.All, _ = config.Config["all"].(bool)
+		options.GroupBy, _ = config.Config["group_by"].(string)
+		if options.GroupBy != "" && options.GroupBy != "compose" && options.GroupBy != "image" && options.GroupBy != "none" {
+			return nil, fmt.Errorf("%s provider 'group_by' must be compose, image or none, got %q", config.Type, options.GroupBy)
+		}
+		options.User, _ = config.Config["user"].(string)
+		filepath = config.Type
+		baseProvider = NewContainerProvider(config.Name, config.Type, options)
 	default:
 		return nil, fmt.Errorf("unknown provider type: %s", config.Type)
 	}
 
-	if appConfig.IsCacheEnabled() {
-		return cache.NewCachedProvider(baseProvider, config.Type, filepath), nil
+	local := config.Type == "vagrant" || config.Type == "libvirt" || config.Type == "docker" || config.Type == "podman"
+	if local && appConfig.IsOffline() {
+		return &skippedProvider{name: config.Name, reason: "skipped in offline mode"}, nil
+	}
+	if !local && (appConfig.IsCacheEnabled() || appConfig.IsOffline()) {
+		return cache.NewCachedProvider(baseProvider, config.Type, filepath, appConfig.IsOffline()), nil
 	}
 
 	return baseProvider, nil
 }
+
+func cloudOptionsFromConfig(config map[string]interface{}) (CloudOptions, error) {
+	var options CloudOptions
+	options.File, _ = config["file"].(string)
+	if command, ok := config["command"].(string); ok {
+		options.Command = strings.Fields(command)
+	} else {
+		options.Command = configStringList(config, "command")
+	}
+	options.Address, _ = config["address"].(string)
+	options.GroupBy, _ = config["group_by"].(string)
+	options.Stopped, _ = config["stopped"].(string)
+	options.User, _ = config["user"].(string)
+	if port, ok := config["port"].(float64); ok {
+		options.Port = int(port)
+	}
+	return options, options.validate()
+}
+
+func resolveToken(token, tokenFile, tokenEnv, tokenFileEnv string) (string, error) {
+	if token != "" {
+		return token, nil
+	}
+
+	if tokenFile == "" {
+		if envToken := os.Getenv(tokenEnv); envToken != "" {
+			return envToken, nil
+		}
+		tokenFile = os.Getenv(tokenFileEnv)
+	}
+
+	if tokenFile == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file %s: %w", tokenFile, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func configStringList(config map[string]interface{}, key string) []string {
+	var values []string
+	switch v := config[key].(type) {
+	case string:
+		values = append(values, v)
+	case []interface{}:
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+	}
+	return values
+}
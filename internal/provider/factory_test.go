@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tech-arch1tect/lssh/internal/cache"
+	pkgprovider "github.com/tech-arch1tect/lssh/pkg/provider"
+)
+
+type testCacheConfig struct {
+	cacheEnabled bool
+	offline      bool
+}
+
+func (c testCacheConfig) IsCacheEnabled() bool {
+	return c.cacheEnabled
+}
+
+func (c testCacheConfig) IsOffline() bool {
+	return c.offline
+}
+
+func TestNewProviderLocalProviders(t *testing.T) {
+	t.Setenv("LSSH_CACHE_DIR", t.TempDir())
+
+	configs := []Config{
+		{Type: "vagrant", Name: "boxes", Config: map[string]interface{}{"directory": "/srv/vagrant"}},
+		{Type: "libvirt", Name: "vms", Config: map[string]interface{}{"uri": "qemu+ssh://hypervisor/system"}},
+		{Type: "docker", Name: "containers", Config: map[string]interface{}{}},
+		{Type: "podman", Name: "pods", Config: map[string]interface{}{}},
+	}
+
+	for _, config := range configs {
+		t.Run(config.Type, func(t *testing.T) {
+			online, err := NewProvider(config, testCacheConfig{cacheEnabled: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, cached := online.(*cache.CachedProvider); cached {
+				t.Error("local provider is cached")
+			}
+
+			offline, err := NewProvider(config, testCacheConfig{cacheEnabled: true, offline: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			groups, err := offline.GetGroups(context.Background())
+			if err != nil || len(groups) != 0 {
+				t.Fatalf("offline groups = %v, %v, want none", groups, err)
+			}
+			wp, ok := offline.(pkgprovider.WarningProvider)
+			if !ok || len(wp.Warnings()) != 1 {
+				t.Errorf("offline provider does not report that it was skipped")
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+type FileSDProvider struct {
+	name    string
+	files   []string
+	options FileSDOptions
+}
+
+type FileSDOptions struct {
+	GroupBy   string
+	NameLabel string
+	User      string
+	Port      int
+}
+
+type fileSDTargetGroup struct {
+	Targets []string          `json:"targets" yaml:"targets"`
+	Labels  map[string]string `json:"labels" yaml:"labels"`
+}
+
+func NewFileSDProvider(name string, files []string, options FileSDOptions) *FileSDProvider {
+	if options.GroupBy == "" {
+		options.GroupBy = "job"
+	}
+
+	return &FileSDProvider{
+		name:    name,
+		files:   files,
+		options: options,
+	}
+}
+
+func (p *FileSDProvider) Name() string {
+	return p.name
+}
+
+func (p *FileSDProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	var paths []string
+	for _, pattern := range p.files {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file_sd pattern %s: %w", pattern, err)
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	var groups []*types.Group
+	groupsByName := make(map[string]*types.Group)
+	hostsByKey := make(map[string]*types.Host)
+
+	for _, path := range paths {
+		targetGroups, err := p.parseFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, targetGroup := range targetGroups {
+			var labels []string
+			for key, value := range targetGroup.Labels {
+				if !strings.HasPrefix(key, "__") {
+					labels = append(labels, key+"="+value)
+				}
+			}
+			sort.Strings(labels)
+
+			groupName := targetGroup.Labels[p.options.GroupBy]
+			if groupName == "" {
+				groupName = "no " + p.options.GroupBy
+			}
+
+			for _, target := range targetGroup.Targets {
+				hostname := target
+				if host, _, err := net.SplitHostPort(target); err == nil {
+					hostname = host
+				}
+				if hostname == "" {
+					continue
+				}
+
+				name := targetGroup.Labels[p.options.NameLabel]
+				if name == "" {
+					name = hostname
+				}
+
+				key := groupName + "\x00" + name + "\x00" + hostname
+				if host, exists := hostsByKey[key]; exists {
+					for _, label := range labels {
+						host.Tags = appendUnique(host.Tags, label)
+					}
+					continue
+				}
+
+				host := &types.Host{
+					Name:     name,
+					Hostname: hostname,
+					User:     p.options.User,
+					Port:     p.options.Port,
+					Tags:     append([]string{}, labels...),
+				}
+				hostsByKey[key] = host
+
+				group, exists := groupsByName[groupName]
+				if !exists {
+					group = &types.Group{Name: groupName, Hosts: []*types.Host{}}
+					groupsByName[groupName] = group
+					groups = append(groups, group)
+				}
+				group.Hosts = append(group.Hosts, host)
+			}
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no targets found in file_sd files %s", strings.Join(p.files, ", "))
+	}
+
+	return groups, nil
+}
+
+func (p *FileSDProvider) parseFile(path string) ([]fileSDTargetGroup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file_sd file %s: %w", path, err)
+	}
+
+	var targetGroups []fileSDTargetGroup
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &targetGroups)
+	default:
+		err = json.Unmarshal(data, &targetGroups)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file_sd file %s: %w", path, err)
+	}
+
+	return targetGroups, nil
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testFileSDTargets(t *testing.T) string {
+	return writeTestFiles(t, map[string]string{
+		"targets/node.json": `[
+  {
+    "targets": ["web01.example.com:9100", "web02.example.com:9100"],
+    "labels": {"job": "node", "env": "prod", "__meta_source": "terraform"}
+  },
+  {
+    "targets": ["[2001:db8::5]:9100"],
+    "labels": {"job": "node", "env": "staging", "hostname": "ipv6-box"}
+  }
+]`,
+		"targets/mysql.yml": `
+- targets:
+    - db01.example.com:9104
+  labels:
+    job: mysql
+    env: prod
+    hostname: db01
+- targets:
+    - web01.example.com:9104
+  labels:
+    env: prod
+`,
+		"targets/blackbox.yaml": `
+- targets: ["web01.example.com:9115"]
+  labels:
+    job: node
+    env: prod
+    probe: http
+`,
+		"targets/README.md": "not a target file",
+	})
+}
+
+func fileSDSummary(t *testing.T, provider *FileSDProvider) (map[string][]string, map[string][]string) {
+	t.Helper()
+
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	members := make(map[string][]string)
+	tags := make(map[string][]string)
+	for _, group := range groups {
+		for _, host := range group.Hosts {
+			members[group.Name] = append(members[group.Name], host.Name+"="+host.Hostname)
+			tags[group.Name+"/"+host.Name] = host.Tags
+		}
+	}
+	return members, tags
+}
+
+func TestFileSDGrouping(t *testing.T) {
+	dir := testFileSDTargets(t)
+	files := []string{filepath.Join(dir, "targets", "*.json"), filepath.Join(dir, "targets", "*.y*ml")}
+
+	tests := []struct {
+		name    string
+		options FileSDOptions
+		want    map[string][]string
+	}{
+		{
+			name: "grouped by job",
+			want: map[string][]string{
+				"node":   {"web01.example.com=web01.example.com", "web02.example.com=web02.example.com", "2001:db8::5=2001:db8::5"},
+				"mysql":  {"db01.example.com=db01.example.com"},
+				"no job": {"web01.example.com=web01.example.com"},
+			},
+		},
+		{
+			name:    "grouped by another label with a name label",
+			options: FileSDOptions{GroupBy: "env", NameLabel: "hostname"},
+			want: map[string][]string{
+				"prod":    {"web01.example.com=web01.example.com", "db01=db01.example.com", "web02.example.com=web02.example.com"},
+				"staging": {"ipv6-box=2001:db8::5"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			members, _ := fileSDSummary(t, NewFileSDProvider("monitoring", files, test.options))
+			if !reflect.DeepEqual(members, test.want) {
+				t.Errorf("groups = %v, want %v", members, test.want)
+			}
+		})
+	}
+}
+
+func TestFileSDMergesLabels(t *testing.T) {
+	dir := testFileSDTargets(t)
+	provider := NewFileSDProvider("monitoring", []string{filepath.Join(dir, "targets", "*")}, FileSDOptions{GroupBy: "env", User: "ops", Port: 2222})
+
+	_, err := provider.GetGroups(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "README.md") {
+		t.Fatalf("error = %v, want the non-target file to be reported", err)
+	}
+
+	provider = NewFileSDProvider("monitoring", []string{filepath.Join(dir, "targets", "*.json"), filepath.Join(dir, "targets", "*.y*ml")}, FileSDOptions{GroupBy: "env", User: "ops", Port: 2222})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, tags := fileSDSummary(t, provider)
+
+	if want := []string{"env=prod", "job=node", "probe=http"}; !reflect.DeepEqual(tags["prod/web01.example.com"], want) {
+		t.Errorf("web01 tags = %v, want %v", tags["prod/web01.example.com"], want)
+	}
+	if want := []string{"env=prod", "job=node"}; !reflect.DeepEqual(tags["prod/web02.example.com"], want) {
+		t.Errorf("web02 tags = %v, want %v (labels starting with __ are skipped)", tags["prod/web02.example.com"], want)
+	}
+
+	host := groups[0].Hosts[0]
+	if host.User != "ops" || host.Port != 2222 {
+		t.Errorf("host = %+v, want the configured user and SSH port", host)
+	}
+}
+
+func TestFileSDErrors(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"empty.json":  "[]",
+		"broken.json": `[{"targets": ["web01:9100"]`,
+	})
+
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{[]string{filepath.Join(dir, "empty.json")}, "no targets found"},
+		{[]string{filepath.Join(dir, "nothing-*.json")}, "no targets found"},
+		{[]string{filepath.Join(dir, "broken.json")}, "failed to parse file_sd file"},
+		{[]string{filepath.Join(dir, "[")}, "invalid file_sd pattern"},
+	}
+
+	for _, test := range tests {
+		_, err := NewFileSDProvider("monitoring", test.files, FileSDOptions{}).GetGroups(context.Background())
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: error = %v, want %q", test.files, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type GCEProvider struct {
+	name    string
+	options CloudOptions
+}
+
+type gceInstance struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Zone              string            `json:"zone"`
+	Status            string            `json:"status"`
+	Labels            map[string]string `json:"labels"`
+	NetworkInterfaces []struct {
+		Network       string `json:"network"`
+		NetworkIP     string `json:"networkIP"`
+		AccessConfigs []struct {
+			NatIP string `json:"natIP"`
+		} `json:"accessConfigs"`
+	} `json:"networkInterfaces"`
+	Tags struct {
+		Items []string `json:"items"`
+	} `json:"tags"`
+}
+
+func NewGCEProvider(name string, options CloudOptions) *GCEProvider {
+	if options.File == "" && len(options.Command) == 0 {
+		options.Command = []string{"gcloud", "compute", "instances", "list", "--format=json"}
+	}
+
+	return &GCEProvider{
+		name:    name,
+		options: options,
+	}
+}
+
+func (p *GCEProvider) Name() string {
+	return p.name
+}
+
+func (p *GCEProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	data, err := loadCloudOutput(ctx, p.options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load GCE instances: %w", err)
+	}
+
+	var output []gceInstance
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse GCE instances list output from %s: %w", p.options.source(), err)
+	}
+
+	var instances []cloudInstance
+	for _, instance := range output {
+		cloud := cloudInstance{
+			name:       instance.Name,
+			running:    instance.Status == "RUNNING",
+			state:      instance.Status,
+			groupNames: map[string]string{"az": path.Base(instance.Zone)},
+			labels:     instance.Labels,
+		}
+
+		if len(instance.NetworkInterfaces) > 0 {
+			nic := instance.NetworkInterfaces[0]
+			cloud.privateIP = nic.NetworkIP
+			cloud.groupNames["vpc"] = path.Base(nic.Network)
+			for _, accessConfig := range nic.AccessConfigs {
+				if accessConfig.NatIP != "" {
+					cloud.publicIP = accessConfig.NatIP
+					break
+				}
+			}
+		}
+
+		for key, value := range instance.Labels {
+			cloud.tags = append(cloud.tags, key+"="+value)
+		}
+		for _, item := range instance.Tags.Items {
+			cloud.tags = append(cloud.tags, "network-tag="+item)
+		}
+		sort.Strings(cloud.tags)
+
+		instances = append(instances, cloud)
+	}
+
+	groups := buildCloudGroups(instances, p.options)
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no GCE instances with an IP address found in %s", p.options.source())
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type HostsFileProvider struct {
+	name            string
+	filepath        string
+	defaultGroup    string
+	includeLoopback bool
+}
+
+type hostsFileDirective struct {
+	groups []string
+	user   string
+	port   int
+	tags   []string
+}
+
+func NewHostsFileProvider(name, filepath, defaultGroup string, includeLoopback bool) *HostsFileProvider {
+	return &HostsFileProvider{
+		name:            name,
+		filepath:        filepath,
+		defaultGroup:    defaultGroup,
+		includeLoopback: includeLoopback,
+	}
+}
+
+func (p *HostsFileProvider) Name() string {
+	return p.name
+}
+
+func (p *HostsFileProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	file, err := os.Open(p.filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hosts file %s: %w", p.filepath, err)
+	}
+	defer file.Close()
+
+	defaultGroup := p.defaultGroup
+	if defaultGroup == "" {
+		defaultGroup = filepath.Base(p.filepath)
+	}
+
+	groups := []*types.Group{}
+	groupsByName := make(map[string]*types.Group)
+	current := hostsFileDirective{}
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		entry, comment, _ := strings.Cut(line, "#")
+		entry = strings.TrimSpace(entry)
+		comment = strings.TrimSpace(comment)
+
+		directive, hasDirective, err := parseHostsFileDirective(comment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lssh directive in %s line %d: %w", p.filepath, lineNumber, err)
+		}
+
+		if entry == "" {
+			if hasDirective {
+				current = directive
+			}
+			continue
+		}
+
+		fields := strings.Fields(entry)
+		if len(fields) < 2 {
+			continue
+		}
+
+		ip := net.ParseIP(fields[0])
+		if ip == nil {
+			continue
+		}
+		if !p.includeLoopback && (ip.IsLoopback() || ip.IsMulticast() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || isIPv6HostsPlaceholder(fields[0])) {
+			continue
+		}
+
+		settings := current
+		if hasDirective {
+			settings = mergeHostsFileDirectives(current, directive)
+		}
+
+		host := &types.Host{
+			Name:     fields[1],
+			Hostname: fields[0],
+			Port:     settings.port,
+			User:     settings.user,
+		}
+		for _, alias := range fields[2:] {
+			host.Tags = append(host.Tags, "alias="+alias)
+		}
+		host.Tags = append(host.Tags, settings.tags...)
+
+		groupNames := settings.groups
+		if len(groupNames) == 0 {
+			groupNames = []string{defaultGroup}
+		}
+
+		for _, groupName := range groupNames {
+			group, exists := groupsByName[groupName]
+			if !exists {
+				group = &types.Group{Name: groupName, Hosts: []*types.Host{}}
+				groupsByName[groupName] = group
+				groups = append(groups, group)
+			}
+			group.Hosts = append(group.Hosts, host)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read hosts file %s: %w", p.filepath, err)
+	}
+
+	return groups, nil
+}
+
+func parseHostsFileDirective(comment string) (hostsFileDirective, bool, error) {
+	var directive hostsFileDirective
+
+	if !strings.HasPrefix(comment, "lssh:") {
+		return directive, false, nil
+	}
+
+	for _, field := range strings.Fields(strings.TrimPrefix(comment, "lssh:")) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return directive, false, fmt.Errorf("expected key=value, got %q", field)
+		}
+
+		switch key {
+		case "group", "groups":
+			directive.groups = splitNonEmpty(value, ",")
+		case "user":
+			directive.user = value
+		case "port":
+			port, err := strconv.Atoi(value)
+			if err != nil || port <= 0 || port > 65535 {
+				return directive, false, fmt.Errorf("invalid port %q", value)
+			}
+			directive.port = port
+		case "tag", "tags":
+			directive.tags = splitNonEmpty(value, ",")
+		default:
+			return directive, false, fmt.Errorf("unknown key %q", key)
+		}
+	}
+
+	return directive, true, nil
+}
+
+func mergeHostsFileDirectives(base, override hostsFileDirective) hostsFileDirective {
+	merged := base
+	if len(override.groups) > 0 {
+		merged.groups = override.groups
+	}
+	if override.user != "" {
+		merged.user = override.user
+	}
+	if override.port != 0 {
+		merged.port = override.port
+	}
+	if len(override.tags) > 0 {
+		merged.tags = append(append([]string{}, base.tags...), override.tags...)
+	}
+	return merged
+}
+
+func isIPv6HostsPlaceholder(address string) bool {
+	return strings.HasPrefix(strings.ToLower(address), "fe00::") || strings.HasPrefix(strings.ToLower(address), "ff00::")
+}
+
+func splitNonEmpty(value, separator string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, separator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func loadHostsFile(t *testing.T, content string, includeLoopback bool) (map[string][]string, map[string]string, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "hosts")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := NewHostsFileProvider("hosts", path, "", includeLoopback).GetGroups(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members := make(map[string][]string)
+	details := make(map[string]string)
+	for _, group := range groups {
+		for _, host := range group.Hosts {
+			members[group.Name] = append(members[group.Name], host.Name)
+			details[host.Name] = strings.Join(append([]string{host.Hostname, host.User, portString(host.Port)}, host.Tags...), " ")
+		}
+	}
+	return members, details, nil
+}
+
+func portString(port int) string {
+	if port == 0 {
+		return "-"
+	}
+	return strconv.Itoa(port)
+}
+
+const defaultEtcHosts = `127.0.0.1	localhost
+127.0.1.1	workstation.example.com	workstation
+
+# The following lines are desirable for IPv6 capable hosts
+::1     ip6-localhost ip6-loopback
+fe00::0 ip6-localnet
+ff00::0 ip6-mcastprefix
+ff02::1 ip6-allnodes
+ff02::2 ip6-allrouters
+`
+
+func TestHostsFileLoopbackOnly(t *testing.T) {
+	members, _, err := loadHostsFile(t, defaultEtcHosts, false)
+	if err != nil {
+		t.Fatalf("a default /etc/hosts made the provider fail: %v", err)
+	}
+	if len(members) != 0 {
+		t.Errorf("groups = %v, want none", members)
+	}
+
+	members, _, err = loadHostsFile(t, defaultEtcHosts, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"localhost", "workstation.example.com", "ip6-localhost", "ip6-localnet", "ip6-mcastprefix", "ip6-allnodes", "ip6-allrouters"}; !reflect.DeepEqual(members["hosts"], want) {
+		t.Errorf("hosts with include_loopback = %v, want %v", members["hosts"], want)
+	}
+}
+
+func TestHostsFileEntries(t *testing.T) {
+	content := defaultEtcHosts + `
+10.0.0.5    gateway gw router   # the office router
+169.254.1.1 link-local
+0.0.0.0     blocked.example.com
+
+# lssh: group=db,backup user=admin port=2222
+10.1.0.20  db-1
+10.1.0.21  db-2   # lssh: port=2200 tags=replica
+# lssh: tags=lab
+10.1.0.25  lab-1  # lssh: group=lab tags=gpu
+# lssh:
+10.1.0.30  misc
+not-an-ip  ignored
+10.1.0.31
+`
+
+	members, details, err := loadHostsFile(t, content, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantMembers := map[string][]string{
+		"hosts":  {"gateway", "misc"},
+		"db":     {"db-1", "db-2"},
+		"backup": {"db-1", "db-2"},
+		"lab":    {"lab-1"},
+	}
+	if !reflect.DeepEqual(members, wantMembers) {
+		t.Errorf("groups = %v, want %v", members, wantMembers)
+	}
+
+	wantDetails := map[string]string{
+		"gateway": "10.0.0.5  - alias=gw alias=router",
+		"db-1":    "10.1.0.20 admin 2222",
+		"db-2":    "10.1.0.21 admin 2200 replica",
+		"lab-1":   "10.1.0.25  - lab gpu",
+		"misc":    "10.1.0.30  -",
+	}
+	if !reflect.DeepEqual(details, wantDetails) {
+		t.Errorf("hosts = %q, want %q", details, wantDetails)
+	}
+}
+
+func TestHostsFileInvalidDirective(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"# lssh: port=abc", `invalid port "abc"`},
+		{"# lssh: port=70000", `invalid port "70000"`},
+		{"# lssh: colour=blue", `unknown key "colour"`},
+		{"10.0.0.1 web # lssh: group", `expected key=value, got "group"`},
+	}
+
+	for _, test := range tests {
+		_, _, err := loadHostsFile(t, "10.0.0.9 ok\n"+test.line+"\n", false)
+		if err == nil || !strings.Contains(err.Error(), test.want) || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("%q: error = %v, want %s on line 2", test.line, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type KnownHostsProvider struct {
+	name  string
+	files []string
+
+	once   sync.Once
+	plain  map[string]bool
+	hashed []hashedKnownHost
+}
+
+type hashedKnownHost struct {
+	salt []byte
+	hash []byte
+}
+
+type knownHostEntry struct {
+	hostname string
+	port     int
+}
+
+func NewKnownHostsProvider(name string, files []string) *KnownHostsProvider {
+	return &KnownHostsProvider{
+		name:  name,
+		files: files,
+	}
+}
+
+func (p *KnownHostsProvider) Name() string {
+	return p.name
+}
+
+func (p *KnownHostsProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	var entries []knownHostEntry
+	for _, file := range p.files {
+		fileEntries, err := p.parseFile(file, nil)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read known_hosts file %s: %w", file, err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	groupsByName := make(map[string]*types.Group)
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		key := fmt.Sprintf("%s:%d", entry.hostname, entry.port)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		host := &types.Host{
+			Name:     entry.hostname,
+			Hostname: entry.hostname,
+			Port:     entry.port,
+			Tags:     []string{"known_hosts"},
+		}
+		if entry.port != 0 {
+			host.Name = fmt.Sprintf("%s:%d", entry.hostname, entry.port)
+		}
+
+		groupName := knownHostsGroupName(entry.hostname)
+		group, exists := groupsByName[groupName]
+		if !exists {
+			group = &types.Group{Name: groupName, Hosts: []*types.Host{}}
+			groupsByName[groupName] = group
+		}
+		group.Hosts = append(group.Hosts, host)
+	}
+
+	var groups []*types.Group
+	for _, group := range groupsByName {
+		sort.Slice(group.Hosts, func(i, j int) bool {
+			return group.Hosts[i].Name < group.Hosts[j].Name
+		})
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups, nil
+}
+
+func (p *KnownHostsProvider) IsKnown(hostname string, port int) bool {
+	p.once.Do(func() {
+		p.plain = make(map[string]bool)
+		for _, file := range p.files {
+			entries, err := p.parseFile(file, &p.hashed)
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				p.plain[knownHostsPattern(entry.hostname, entry.port)] = true
+			}
+		}
+	})
+
+	pattern := knownHostsPattern(hostname, port)
+	if p.plain[pattern] {
+		return true
+	}
+
+	for _, hashed := range p.hashed {
+		mac := hmac.New(sha1.New, hashed.salt)
+		mac.Write([]byte(pattern))
+		if hmac.Equal(mac.Sum(nil), hashed.hash) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *KnownHostsProvider) parseFile(file string, hashed *[]hashedKnownHost) ([]knownHostEntry, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []knownHostEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if strings.HasPrefix(fields[0], "@") {
+			continue
+		}
+		if len(fields) < 3 {
+			continue
+		}
+
+		if strings.HasPrefix(fields[0], "|1|") {
+			if hashed != nil {
+				if entry, ok := parseHashedKnownHost(fields[0]); ok {
+					*hashed = append(*hashed, entry)
+				}
+			}
+			continue
+		}
+
+		for _, pattern := range strings.Split(fields[0], ",") {
+			if pattern == "" || strings.ContainsAny(pattern, "*?!") {
+				continue
+			}
+			hostname, port := parseKnownHostsPattern(pattern)
+			entries = append(entries, knownHostEntry{hostname: hostname, port: port})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func parseHashedKnownHost(field string) (hashedKnownHost, bool) {
+	parts := strings.Split(strings.TrimPrefix(field, "|1|"), "|")
+	if len(parts) != 2 {
+		return hashedKnownHost{}, false
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return hashedKnownHost{}, false
+	}
+	hash, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return hashedKnownHost{}, false
+	}
+
+	return hashedKnownHost{salt: salt, hash: hash}, true
+}
+
+func parseKnownHostsPattern(pattern string) (string, int) {
+	if strings.HasPrefix(pattern, "[") {
+		if end := strings.Index(pattern, "]:"); end > 0 {
+			if port, err := strconv.Atoi(pattern[end+2:]); err == nil {
+				if port == 22 {
+					port = 0
+				}
+				return pattern[1:end], port
+			}
+		}
+		return strings.Trim(pattern, "[]"), 0
+	}
+	return pattern, 0
+}
+
+func knownHostsPattern(hostname string, port int) string {
+	if port == 0 || port == 22 {
+		return hostname
+	}
+	return fmt.Sprintf("[%s]:%d", hostname, port)
+}
+
+func knownHostsGroupName(hostname string) string {
+	if net.ParseIP(hostname) != nil {
+		return "IP addresses"
+	}
+
+	if dot := strings.Index(hostname, "."); dot > 0 && dot < len(hostname)-1 {
+		return hostname[dot+1:]
+	}
+
+	return "Short names"
+}
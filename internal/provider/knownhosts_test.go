@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const testHostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+
+func writeKnownHosts(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func testKnownHostsFile(t *testing.T) string {
+	return writeKnownHosts(t, `# managed by config management
+
+web01.prod.example.com,10.0.0.5 `+testHostKey+`
+[bastion.example.com]:2222 `+testHostKey+`
+[git.example.com]:22 `+testHostKey+` git@example
+db `+testHostKey+`
+`+knownhosts.HashHostname("hidden.example.com")+` `+testHostKey+`
+`+knownhosts.HashHostname("[hidden2.example.com]:2200")+` `+testHostKey+`
+@revoked bad.example.com `+testHostKey+`
+@cert-authority *.example.com `+testHostKey+`
+*.lab.example.com,!gateway.lab.example.com `+testHostKey+`
+incomplete.example.com
+`)
+}
+
+func TestKnownHostsGroups(t *testing.T) {
+	provider := NewKnownHostsProvider("known_hosts", []string{testKnownHostsFile(t)})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string][]string)
+	ports := make(map[string]int)
+	for _, group := range groups {
+		for _, host := range group.Hosts {
+			got[group.Name] = append(got[group.Name], host.Name)
+			ports[host.Name] = host.Port
+		}
+	}
+
+	want := map[string][]string{
+		"IP addresses":     {"10.0.0.5"},
+		"Short names":      {"db"},
+		"example.com":      {"bastion.example.com:2222", "git.example.com"},
+		"prod.example.com": {"web01.prod.example.com"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groups = %v, want %v", got, want)
+	}
+	if ports["bastion.example.com:2222"] != 2222 || ports["git.example.com"] != 0 {
+		t.Errorf("ports = %v, want 2222 for the bastion and the default for git", ports)
+	}
+}
+
+func TestKnownHostsIsKnown(t *testing.T) {
+	provider := NewKnownHostsProvider("known_hosts", []string{testKnownHostsFile(t)})
+
+	tests := []struct {
+		hostname string
+		port     int
+		want     bool
+	}{
+		{"web01.prod.example.com", 0, true},
+		{"web01.prod.example.com", 22, true},
+		{"web01.prod.example.com", 2222, false},
+		{"10.0.0.5", 0, true},
+		{"bastion.example.com", 2222, true},
+		{"bastion.example.com", 0, false},
+		{"git.example.com", 22, true},
+		{"hidden.example.com", 0, true},
+		{"hidden.example.com", 2200, false},
+		{"hidden2.example.com", 2200, true},
+		{"hidden2.example.com", 0, false},
+		{"bad.example.com", 0, false},
+		{"other.example.com", 0, false},
+		{"host.lab.example.com", 0, false},
+		{"incomplete.example.com", 0, false},
+	}
+
+	for _, test := range tests {
+		if got := provider.IsKnown(test.hostname, test.port); got != test.want {
+			t.Errorf("IsKnown(%s, %d) = %v, want %v", test.hostname, test.port, got, test.want)
+		}
+	}
+}
+
+func TestKnownHostsWithoutListableHosts(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+	}{
+		{"empty file", []string{writeKnownHosts(t, "")}},
+		{"missing file", []string{filepath.Join(t.TempDir(), "known_hosts")}},
+		{"only hashed entries", []string{writeKnownHosts(t, knownhosts.HashHostname("web01.example.com")+" "+testHostKey+"\n")}},
+		{"only markers and comments", []string{writeKnownHosts(t, "# nothing\n@revoked * "+testHostKey+"\n")}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groups, err := NewKnownHostsProvider("known_hosts", test.files).GetGroups(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(groups) != 0 {
+				t.Errorf("groups = %+v, want none", groups)
+			}
+		})
+	}
+}
+
+func TestKnownHostsUnreadableFile(t *testing.T) {
+	_, err := NewKnownHostsProvider("known_hosts", []string{t.TempDir()}).GetGroups(context.Background())
+	if err == nil {
+		t.Fatal("reading a directory as known_hosts succeeded")
+	}
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type LibvirtProvider struct {
+	name     string
+	options  LibvirtOptions
+	warnings []string
+}
+
+type LibvirtOptions struct {
+	URI          string
+	Source       string
+	Group        string
+	User         string
+	Port         int
+	IdentityFile string
+}
+
+func NewLibvirtProvider(name string, options LibvirtOptions) *LibvirtProvider {
+	if options.URI == "" {
+		options.URI = "qemu:///system"
+	}
+	if options.Group == "" {
+		options.Group = "libvirt"
+	}
+
+	return &LibvirtProvider{
+		name:    name,
+		options: options,
+	}
+}
+
+func (p *LibvirtProvider) Name() string {
+	return p.name
+}
+
+func (p *LibvirtProvider) Warnings() []string {
+	return p.warnings
+}
+
+func (p *LibvirtProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	p.warnings = nil
+
+	output, err := p.virsh(ctx, "list", "--name")
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		p.warnings = append(p.warnings, err.Error())
+		return []*types.Group{}, nil
+	}
+
+	group := &types.Group{Name: p.options.Group, Description: p.options.URI, Hosts: []*types.Host{}}
+	for _, domain := range strings.Split(output, "\n") {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			continue
+		}
+
+		args := []string{"domifaddr", domain}
+		if p.options.Source != "" {
+			args = append(args, "--source", p.options.Source)
+		}
+		addresses, err := p.virsh(ctx, args...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			p.warnings = append(p.warnings, fmt.Sprintf("%s: %v", domain, err))
+			continue
+		}
+
+		address := parseDomIfAddr(addresses)
+		if address == "" {
+			continue
+		}
+
+		group.Hosts = append(group.Hosts, &types.Host{
+			Name:         domain,
+			Hostname:     address,
+			User:         p.options.User,
+			Port:         p.options.Port,
+			IdentityFile: p.options.IdentityFile,
+			Tags:         []string{"libvirt=" + p.options.URI},
+		})
+	}
+
+	if len(group.Hosts) == 0 {
+		return []*types.Group{}, nil
+	}
+
+	return []*types.Group{group}, nil
+}
+
+func (p *LibvirtProvider) virsh(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "virsh", append([]string{"-c", p.options.URI}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("virsh %s failed: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("failed to run virsh: %w", err)
+	}
+	return string(output), nil
+}
+
+func parseDomIfAddr(output string) string {
+	var ipv6 string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		address := strings.SplitN(fields[len(fields)-1], "/", 2)[0]
+		switch fields[len(fields)-2] {
+		case "ipv4":
+			return address
+		case "ipv6":
+			if ipv6 == "" {
+				ipv6 = address
+			}
+		}
+	}
+	return ipv6
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func fakeCommand(t *testing.T, name, script string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestVagrantWithoutRunningMachines(t *testing.T) {
+	fakeCommand(t, "vagrant", `echo "The provider for this Vagrant-managed machine is reporting that it is not yet ready for SSH." >&2
+exit 1
+`)
+
+	provider := NewVagrantProvider("vagrant", []string{t.TempDir()})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatalf("halted machines made the provider fail: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("groups = %+v, want none", groups)
+	}
+	if warnings := provider.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "not yet ready") {
+		t.Errorf("warnings = %q, want the vagrant error", warnings)
+	}
+}
+
+func TestVagrantRunningMachine(t *testing.T) {
+	fakeCommand(t, "vagrant", `cat <<'CONFIG'
+Host default
+  HostName 127.0.0.1
+  User vagrant
+  Port 2222
+  IdentityFile /home/me/api/.vagrant/machines/default/virtualbox/private_key
+  StrictHostKeyChecking no
+CONFIG
+`)
+
+	directory := filepath.Join(t.TempDir(), "api")
+	if err := os.Mkdir(directory, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewVagrantProvider("vagrant", []string{directory})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || len(groups[0].Hosts) != 1 {
+		t.Fatalf("groups = %+v, want one group with one host", groups)
+	}
+
+	host := groups[0].Hosts[0]
+	if host.Name != "api" || host.Hostname != "127.0.0.1" || host.Port != 2222 || host.User != "vagrant" {
+		t.Errorf("host = %+v", host)
+	}
+	if want := []string{"StrictHostKeyChecking=no"}; !reflect.DeepEqual(host.SSHOptions, want) {
+		t.Errorf("ssh options = %v, want %v", host.SSHOptions, want)
+	}
+	if len(provider.Warnings()) != 0 {
+		t.Errorf("warnings = %q, want none", provider.Warnings())
+	}
+}
+
+func TestLibvirtDomains(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		hosts    []string
+		warnings []string
+	}{
+		{
+			name:   "no running domains",
+			script: "echo\n",
+		},
+		{
+			name:     "unreachable URI",
+			script:   "echo 'error: failed to connect to the hypervisor' >&2\nexit 1\n",
+			warnings: []string{"virsh list failed: error: failed to connect to the hypervisor"},
+		},
+		{
+			name: "failed domifaddr is reported",
+			script: `case "$3" in
+list) printf 'web\nbroken\nbooting\n' ;;
+domifaddr)
+  case "$4" in
+  web) printf ' Name       MAC address          Protocol     Address\n----------------------------------------------------------\n vnet0      52:54:00:aa:bb:cc    ipv6         fe80::1/64\n vnet0      52:54:00:aa:bb:cc    ipv4         192.168.122.10/24\n' ;;
+  broken) echo 'error: Guest agent is not responding' >&2; exit 1 ;;
+  esac ;;
+esac
+`,
+			hosts:    []string{"web=192.168.122.10"},
+			warnings: []string{"broken: virsh domifaddr failed: error: Guest agent is not responding"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeCommand(t, "virsh", test.script)
+
+			provider := NewLibvirtProvider("libvirt", LibvirtOptions{})
+			groups, err := provider.GetGroups(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var hosts []string
+			for _, group := range groups {
+				for _, host := range group.Hosts {
+					hosts = append(hosts, host.Name+"="+host.Hostname)
+				}
+			}
+			if !reflect.DeepEqual(hosts, test.hosts) {
+				t.Errorf("hosts = %v, want %v", hosts, test.hosts)
+			}
+			if !reflect.DeepEqual(provider.Warnings(), test.warnings) {
+				t.Errorf("warnings = %q, want %q", provider.Warnings(), test.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type NetBoxProvider struct {
+	name    string
+	options NetBoxOptions
+	client  *http.Client
+}
+
+type NetBoxOptions struct {
+	URL      string
+	Token    string
+	Filters  map[string][]string
+	Include  []string
+	Address  string
+	GroupBy  string
+	PageSize int
+	User     string
+	Port     int
+}
+
+type netboxPage struct {
+	Next    string         `json:"next"`
+	Results []netboxObject `json:"results"`
+}
+
+type netboxObject struct {
+	Name       string       `json:"name"`
+	Display    string       `json:"display"`
+	PrimaryIP  *netboxIP    `json:"primary_ip"`
+	PrimaryIP4 *netboxIP    `json:"primary_ip4"`
+	PrimaryIP6 *netboxIP    `json:"primary_ip6"`
+	Site       *netboxRef   `json:"site"`
+	Role       *netboxRef   `json:"role"`
+	DeviceRole *netboxRef   `json:"device_role"`
+	Tenant     *netboxRef   `json:"tenant"`
+	Status     *netboxValue `json:"status"`
+	Tags       []netboxRef  `json:"tags"`
+}
+
+type netboxIP struct {
+	Address string `json:"address"`
+}
+
+type netboxRef struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type netboxValue struct {
+	Value string `json:"value"`
+}
+
+var netboxEndpoints = map[string]string{
+	"devices":          "/api/dcim/devices/",
+	"virtual_machines": "/api/virtualization/virtual-machines/",
+}
+
+func NewNetBoxProvider(name string, options NetBoxOptions) *NetBoxProvider {
+	options.URL = strings.TrimRight(options.URL, "/")
+	if len(options.Include) == 0 {
+		options.Include = []string{"devices", "virtual_machines"}
+	}
+	if options.Address == "" {
+		options.Address = "primary"
+	}
+	if options.GroupBy == "" {
+		options.GroupBy = "site"
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = 100
+	}
+
+	return &NetBoxProvider{
+		name:    name,
+		options: options,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *NetBoxProvider) Name() string {
+	return p.name
+}
+
+func (p *NetBoxProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	var groups []*types.Group
+	groupsByName := make(map[string]*types.Group)
+
+	for _, kind := range p.options.Include {
+		objects, err := p.list(ctx, netboxEndpoints[kind])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range objects {
+			host := p.objectHost(kind, object)
+			if host == nil {
+				continue
+			}
+
+			groupName := p.groupName(object)
+			group, exists := groupsByName[groupName]
+			if !exists {
+				group = &types.Group{Name: groupName, Hosts: []*types.Host{}}
+				groupsByName[groupName] = group
+				groups = append(groups, group)
+			}
+			group.Hosts = append(group.Hosts, host)
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no devices or virtual machines with a primary IP found in NetBox at %s", p.options.URL)
+	}
+
+	return groups, nil
+}
+
+func (p *NetBoxProvider) list(ctx context.Context, endpoint string) ([]netboxObject, error) {
+	query := url.Values{}
+	for key, values := range p.options.Filters {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	query.Set("limit", strconv.Itoa(p.options.PageSize))
+
+	base, err := url.Parse(p.options.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NetBox URL %q: %w", p.options.URL, err)
+	}
+
+	var objects []netboxObject
+	next := p.options.URL + endpoint + "?" + query.Encode()
+	for next != "" {
+		page, err := p.get(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Results...)
+
+		next = page.Next
+		if next != "" {
+			nextURL, err := url.Parse(next)
+			if err != nil {
+				return nil, fmt.Errorf("invalid next page URL %q in NetBox response: %w", next, err)
+			}
+			if nextURL.Scheme != base.Scheme || nextURL.Host != base.Host {
+				return nil, fmt.Errorf("NetBox returned a next page URL on %s://%s instead of %s://%s, refusing to send the API token there (check the NetBox BASE_PATH and proxy headers)", nextURL.Scheme, nextURL.Host, base.Scheme, base.Host)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+func (p *NetBoxProvider) source() string {
+	query := url.Values{}
+	for key, values := range p.options.Filters {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	query.Set("include", strings.Join(p.options.Include, ","))
+	query.Set("address", p.options.Address)
+	query.Set("group_by", p.options.GroupBy)
+
+	return p.options.URL + "?" + query.Encode()
+}
+
+func (p *NetBoxProvider) get(ctx context.Context, requestURL string) (*netboxPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NetBox request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.options.Token != "" {
+		req.Header.Set("Authorization", "Token "+p.options.Token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query NetBox at %s: %w", p.options.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("NetBox request %s failed: %s: %s", req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var page netboxPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to parse NetBox response from %s: %w", req.URL.Path, err)
+	}
+
+	return &page, nil
+}
+
+func (p *NetBoxProvider) objectHost(kind string, object netboxObject) *types.Host {
+	var ip *netboxIP
+	switch p.options.Address {
+	case "primary4":
+		ip = object.PrimaryIP4
+	case "primary6":
+		ip = object.PrimaryIP6
+	default:
+		ip = object.PrimaryIP
+	}
+	if ip == nil || ip.Address == "" {
+		return nil
+	}
+
+	name := object.Name
+	if name == "" {
+		name = object.Display
+	}
+
+	host := &types.Host{
+		Name:     name,
+		Hostname: strings.SplitN(ip.Address, "/", 2)[0],
+		User:     p.options.User,
+		Port:     p.options.Port,
+		Tags:     []string{"netbox=" + strings.TrimSuffix(kind, "s")},
+	}
+	if object.Status != nil && object.Status.Value != "" {
+		host.Tags = append(host.Tags, "status="+object.Status.Value)
+	}
+	for _, tag := range object.Tags {
+		host.Tags = append(host.Tags, tag.Name)
+	}
+
+	return host
+}
+
+func (p *NetBoxProvider) groupName(object netboxObject) string {
+	var ref *netboxRef
+	switch p.options.GroupBy {
+	case "role":
+		ref = object.Role
+		if ref == nil {
+			ref = object.DeviceRole
+		}
+	case "tenant":
+		ref = object.Tenant
+	default:
+		ref = object.Site
+	}
+
+	if ref == nil || ref.Name == "" {
+		return "no " + p.options.GroupBy
+	}
+	return ref.Name
+}
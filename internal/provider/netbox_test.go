@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNetBoxPagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("request to %s without the API token", r.URL)
+		}
+
+		page := map[string]interface{}{}
+		switch r.URL.Query().Get("offset") {
+		case "":
+			page["next"] = server.URL + r.URL.Path + "?limit=1&offset=1"
+			page["results"] = []map[string]interface{}{{"name": "web1", "primary_ip": map[string]string{"address": "10.0.0.1/24"}, "site": map[string]string{"name": "dc1"}}}
+		default:
+			page["results"] = []map[string]interface{}{{"name": "web2", "primary_ip": map[string]string{"address": "10.0.0.2/24"}, "site": map[string]string{"name": "dc1"}}}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	provider := NewNetBoxProvider("netbox", NetBoxOptions{URL: server.URL, Token: "secret", Include: []string{"devices"}, PageSize: 1})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || len(groups[0].Hosts) != 2 {
+		t.Fatalf("groups = %+v, want one group with two hosts", groups)
+	}
+}
+
+func TestNetBoxRefusesForeignNextURL(t *testing.T) {
+	leaked := make(chan string, 1)
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked <- r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"results": []}`))
+	}))
+	defer foreign.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"next":    foreign.URL + r.URL.Path + "?offset=1",
+			"results": []map[string]interface{}{},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewNetBoxProvider("netbox", NetBoxOptions{URL: server.URL, Token: "secret", Include: []string{"devices"}})
+	_, err := provider.GetGroups(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "refusing to send the API token") {
+		t.Fatalf("error = %v, want the next page URL to be refused", err)
+	}
+
+	select {
+	case token := <-leaked:
+		t.Fatalf("the API token %q was sent to another host", token)
+	default:
+	}
+}
+
+func TestNetBoxSourceIncludesFilters(t *testing.T) {
+	newSource := func(filters map[string][]string, groupBy string) string {
+		return NewNetBoxProvider("netbox", NetBoxOptions{URL: "https://netbox.example.com/", Filters: filters, GroupBy: groupBy}).source()
+	}
+
+	base := newSource(map[string][]string{"site": {"dc1"}, "tag": {"linux"}}, "")
+	if base != newSource(map[string][]string{"tag": {"linux"}, "site": {"dc1"}}, "") {
+		t.Error("source depends on filter map order")
+	}
+
+	for _, other := range []string{
+		newSource(map[string][]string{"site": {"dc2"}, "tag": {"linux"}}, ""),
+		newSource(map[string][]string{"site": {"dc1"}}, ""),
+		newSource(map[string][]string{"site": {"dc1"}, "tag": {"linux"}, "status": {"active"}}, ""),
+		newSource(map[string][]string{"site": {"dc1"}, "tag": {"linux"}}, "role"),
+	} {
+		if other == base {
+			t.Errorf("source %q does not change with the filters", base)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package provider
 
 import (
+	"context"
+
 	pkgprovider "github.com/tech-arch1tect/lssh/pkg/provider"
+	"github.com/tech-arch1tect/lssh/pkg/types"
 )
 
 type Provider = pkgprovider.Provider
@@ -11,3 +14,20 @@ type Config struct {
 	Name   string                 `json:"name"`
 	Config map[string]interface{} `json:"config"`
 }
+
+type skippedProvider struct {
+	name   string
+	reason string
+}
+
+func (p *skippedProvider) Name() string {
+	return p.name
+}
+
+func (p *skippedProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	return []*types.Group{}, nil
+}
+
+func (p *skippedProvider) Warnings() []string {
+	return []string{p.reason}
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type TerraformProvider struct {
+	name     string
+	filepath string
+	options  TerraformOptions
+}
+
+type TerraformOptions struct {
+	ResourceTypes     []string
+	AddressAttributes []string
+	NameAttributes    []string
+	GroupBy           string
+	User              string
+	Port              int
+}
+
+type terraformState struct {
+	Version   int                 `json:"version"`
+	Resources []terraformResource `json:"resources"`
+}
+
+type terraformResource struct {
+	Module    string              `json:"module"`
+	Mode      string              `json:"mode"`
+	Type      string              `json:"type"`
+	Name      string              `json:"name"`
+	Instances []terraformInstance `json:"instances"`
+}
+
+type terraformInstance struct {
+	IndexKey   interface{}            `json:"index_key"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+var defaultTerraformResourceTypes = []string{
+	"aws_instance",
+	"google_compute_instance",
+	"libvirt_domain",
+	"azurerm_linux_virtual_machine",
+	"digitalocean_droplet",
+	"hcloud_server",
+}
+
+var defaultTerraformAddressAttributes = []string{
+	"public_ip",
+	"private_ip",
+	"network_interface.0.access_config.0.nat_ip",
+	"network_interface.0.network_ip",
+	"network_interface.0.addresses.0",
+	"public_ip_address",
+	"private_ip_address",
+	"ipv4_address",
+}
+
+var defaultTerraformNameAttributes = []string{"tags.Name", "labels.name", "name"}
+
+func NewTerraformProvider(name, filepath string, options TerraformOptions) *TerraformProvider {
+	if len(options.ResourceTypes) == 0 {
+		options.ResourceTypes = defaultTerraformResourceTypes
+	}
+	if len(options.AddressAttributes) == 0 {
+		options.AddressAttributes = defaultTerraformAddressAttributes
+	}
+	if len(options.NameAttributes) == 0 {
+		options.NameAttributes = defaultTerraformNameAttributes
+	}
+	if options.GroupBy == "" {
+		options.GroupBy = "module"
+	}
+
+	return &TerraformProvider{
+		name:     name,
+		filepath: filepath,
+		options:  options,
+	}
+}
+
+func (p *TerraformProvider) Name() string {
+	return p.name
+}
+
+func (p *TerraformProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	data, err := os.ReadFile(p.filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Terraform state %s: %w", p.filepath, err)
+	}
+
+	var state terraformState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse Terraform state %s: %w", p.filepath, err)
+	}
+
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported Terraform state version %d in %s (expected 4)", state.Version, p.filepath)
+	}
+
+	wantedTypes := make(map[string]bool)
+	for _, resourceType := range p.options.ResourceTypes {
+		wantedTypes[resourceType] = true
+	}
+
+	var groups []*types.Group
+	groupsByName := make(map[string]*types.Group)
+
+	for _, resource := range state.Resources {
+		if resource.Mode != "managed" || !wantedTypes[resource.Type] {
+			continue
+		}
+
+		for _, instance := range resource.Instances {
+			address := terraformAddress(resource, instance)
+
+			hostname := firstAttribute(instance.Attributes, p.options.AddressAttributes)
+			if hostname == "" {
+				continue
+			}
+
+			name := firstAttribute(instance.Attributes, p.options.NameAttributes)
+			if name == "" {
+				name = address
+			}
+
+			host := &types.Host{
+				Name:     name,
+				Hostname: hostname,
+				User:     p.options.User,
+				Port:     p.options.Port,
+				Tags:     append([]string{"terraform=" + address}, terraformTags(instance.Attributes)...),
+			}
+
+			groupName := p.groupName(resource, instance)
+			group, exists := groupsByName[groupName]
+			if !exists {
+				group = &types.Group{Name: groupName, Hosts: []*types.Host{}}
+				groupsByName[groupName] = group
+				groups = append(groups, group)
+			}
+			group.Hosts = append(group.Hosts, host)
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no instances of %s with an address found in Terraform state %s",
+			strings.Join(p.options.ResourceTypes, ", "), p.filepath)
+	}
+
+	return groups, nil
+}
+
+func (p *TerraformProvider) groupName(resource terraformResource, instance terraformInstance) string {
+	switch {
+	case p.options.GroupBy == "type":
+		return resource.Type
+	case strings.HasPrefix(p.options.GroupBy, "tag:"):
+		key := strings.TrimPrefix(p.options.GroupBy, "tag:")
+		if value := firstAttribute(instance.Attributes, []string{"tags." + key, "labels." + key}); value != "" {
+			return value
+		}
+		return "untagged"
+	default:
+		if resource.Module == "" {
+			return "root"
+		}
+		return strings.ReplaceAll(strings.TrimPrefix(resource.Module, "module."), ".module.", "/")
+	}
+}
+
+func terraformAddress(resource terraformResource, instance terraformInstance) string {
+	address := resource.Type + "." + resource.Name
+	if resource.Module != "" {
+		address = resource.Module + "." + address
+	}
+
+	switch key := instance.IndexKey.(type) {
+	case float64:
+		address += fmt.Sprintf("[%d]", int(key))
+	case string:
+		address += fmt.Sprintf("[%q]", key)
+	}
+
+	return address
+}
+
+func terraformTags(attributes map[string]interface{}) []string {
+	var tags []string
+	for _, attribute := range []string{"tags", "labels"} {
+		values, ok := attributes[attribute].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range values {
+			if str, ok := value.(string); ok {
+				tags = append(tags, key+"="+str)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func firstAttribute(attributes map[string]interface{}, paths []string) string {
+	for _, path := range paths {
+		if value := lookupAttribute(attributes, path); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func lookupAttribute(attributes map[string]interface{}, path string) string {
+	var current interface{} = attributes
+	for _, part := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			current = v[part]
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return ""
+			}
+			current = v[index]
+		default:
+			return ""
+		}
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTerraformState(t *testing.T) {
+	tests := []struct {
+		name    string
+		options TerraformOptions
+		want    map[string][]string
+	}{
+		{
+			name: "default resource types grouped by module",
+			want: map[string][]string{
+				"root":   {"web-0=203.0.113.10", "web-1=10.0.1.11", "builder=95.216.0.7"},
+				"db":     {"db-primary=34.120.0.5"},
+				"lab/vm": {`module.lab.module.vm.libvirt_domain.node["alpha"]=192.168.122.20`},
+			},
+		},
+		{
+			name:    "grouped by type",
+			options: TerraformOptions{GroupBy: "type"},
+			want: map[string][]string{
+				"aws_instance":            {"web-0=203.0.113.10", "web-1=10.0.1.11"},
+				"google_compute_instance": {"db-primary=34.120.0.5"},
+				"libvirt_domain":          {`module.lab.module.vm.libvirt_domain.node["alpha"]=192.168.122.20`},
+				"hcloud_server":           {"builder=95.216.0.7"},
+			},
+		},
+		{
+			name:    "grouped by tag",
+			options: TerraformOptions{GroupBy: "tag:Environment"},
+			want: map[string][]string{
+				"staging":  {"web-0=203.0.113.10", "web-1=10.0.1.11"},
+				"untagged": {"db-primary=34.120.0.5", `module.lab.module.vm.libvirt_domain.node["alpha"]=192.168.122.20`, "builder=95.216.0.7"},
+			},
+		},
+		{
+			name:    "grouped by label",
+			options: TerraformOptions{GroupBy: "tag:environment"},
+			want: map[string][]string{
+				"untagged":   {"web-0=203.0.113.10", "web-1=10.0.1.11", `module.lab.module.vm.libvirt_domain.node["alpha"]=192.168.122.20`},
+				"production": {"db-primary=34.120.0.5"},
+				"ci":         {"builder=95.216.0.7"},
+			},
+		},
+		{
+			name: "custom types and attributes",
+			options: TerraformOptions{
+				ResourceTypes:     []string{"aws_instance"},
+				AddressAttributes: []string{"private_ip"},
+				NameAttributes:    []string{"id"},
+			},
+			want: map[string][]string{
+				"root": {"i-0a1b2c3d4e5f60001=10.0.1.10", "i-0a1b2c3d4e5f60002=10.0.1.11"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := NewTerraformProvider("terraform", filepath.Join("testdata", "terraform.tfstate"), test.options)
+			groups, err := provider.GetGroups(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(map[string][]string)
+			for _, group := range groups {
+				for _, host := range group.Hosts {
+					got[group.Name] = append(got[group.Name], host.Name+"="+host.Hostname)
+				}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("groups = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTerraformHostDetails(t *testing.T) {
+	provider := NewTerraformProvider("terraform", filepath.Join("testdata", "terraform.tfstate"), TerraformOptions{User: "ubuntu", Port: 2222})
+	groups, err := provider.GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := groups[0].Hosts[0]
+	if host.User != "ubuntu" || host.Port != 2222 {
+		t.Errorf("host = %+v, want the configured user and port", host)
+	}
+	if want := []string{"terraform=aws_instance.web[0]", "Environment=staging", "Name=web-0"}; !reflect.DeepEqual(host.Tags, want) {
+		t.Errorf("tags = %v, want %v", host.Tags, want)
+	}
+}
+
+func TestTerraformUnsupportedStateVersion(t *testing.T) {
+	for _, state := range []string{
+		`{"version": 3, "modules": [{"path": ["root"], "resources": {}}]}`,
+		`{"version": 5, "resources": []}`,
+		`{"resources": []}`,
+	} {
+		path := filepath.Join(t.TempDir(), "terraform.tfstate")
+		if err := os.WriteFile(path, []byte(state), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := NewTerraformProvider("terraform", path, TerraformOptions{}).GetGroups(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "unsupported Terraform state version") {
+			t.Errorf("state %s: error = %v, want the version to be rejected", state, err)
+		}
+	}
+}
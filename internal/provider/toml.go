@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type TOMLProvider struct {
+	name     string
+	filepath string
+}
+
+type tomlDocument struct {
+	Groups []*types.Group `toml:"groups"`
+}
+
+func NewTOMLProvider(name, filepath string) *TOMLProvider {
+	return &TOMLProvider{
+		name:     name,
+		filepath: filepath,
+	}
+}
+
+func (p *TOMLProvider) Name() string {
+	return p.name
+}
+
+func (p *TOMLProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	data, err := os.ReadFile(p.filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TOML file %s: %w", p.filepath, err)
+	}
+
+	var document tomlDocument
+	if err := toml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse TOML file %s: %w", p.filepath, err)
+	}
+
+	totalHosts := 0
+	for _, group := range document.Groups {
+		totalHosts += len(group.AllHosts())
+	}
+
+	if totalHosts == 0 {
+		return nil, fmt.Errorf("no hosts found in TOML file %s", p.filepath)
+	}
+
+	return document.Groups, nil
+}
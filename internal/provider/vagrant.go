@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type VagrantProvider struct {
+	name        string
+	directories []string
+	warnings    []string
+}
+
+func NewVagrantProvider(name string, directories []string) *VagrantProvider {
+	return &VagrantProvider{
+		name:        name,
+		directories: directories,
+	}
+}
+
+func (p *VagrantProvider) Name() string {
+	return p.name
+}
+
+func (p *VagrantProvider) Warnings() []string {
+	return p.warnings
+}
+
+func (p *VagrantProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	groups := []*types.Group{}
+	p.warnings = nil
+
+	for _, directory := range p.directories {
+		cmd := exec.CommandContext(ctx, "vagrant", "ssh-config")
+		cmd.Dir = directory
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		runErr := cmd.Run()
+
+		project := filepath.Base(directory)
+		hosts := parseSSHConfig(stdout.String())
+		if len(hosts) == 0 {
+			if runErr != nil {
+				p.warnings = append(p.warnings, fmt.Sprintf("%s: %s", directory, firstLine(stderr.String(), runErr)))
+			}
+			continue
+		}
+
+		group := &types.Group{Name: project, Description: directory, Hosts: []*types.Host{}}
+		for _, host := range hosts {
+			if host.Name == "default" {
+				host.Name = project
+			} else {
+				host.Name = project + "/" + host.Name
+			}
+			host.Tags = append(host.Tags, "vagrant="+directory)
+			group.Hosts = append(group.Hosts, host)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func parseSSHConfig(output string) []*types.Host {
+	var hosts []*types.Host
+	var current *types.Host
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		if strings.EqualFold(key, "Host") {
+			current = &types.Host{Name: value}
+			hosts = append(hosts, current)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "hostname":
+			current.Hostname = value
+		case "user":
+			current.User = value
+		case "port":
+			if port, err := strconv.Atoi(value); err == nil {
+				current.Port = port
+			}
+		case "identityfile":
+			if current.IdentityFile == "" {
+				current.IdentityFile = value
+			} else {
+				current.SSHOptions = append(current.SSHOptions, "IdentityFile="+value)
+			}
+		default:
+			current.SSHOptions = append(current.SSHOptions, key+"="+value)
+		}
+	}
+
+	var complete []*types.Host
+	for _, host := range hosts {
+		if host.Hostname != "" {
+			complete = append(complete, host)
+		}
+	}
+	return complete
+}
+
+func firstLine(output string, err error) string {
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return err.Error()
+}
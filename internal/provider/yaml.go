@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+type YAMLProvider struct {
+	name     string
+	filepath string
+}
+
+type yamlDocument struct {
+	Groups []*types.Group `yaml:"groups"`
+}
+
+func NewYAMLProvider(name, filepath string) *YAMLProvider {
+	return &YAMLProvider{
+		name:     name,
+		filepath: filepath,
+	}
+}
+
+func (p *YAMLProvider) Name() string {
+	return p.name
+}
+
+func (p *YAMLProvider) GetGroups(ctx context.Context) ([]*types.Group, error) {
+	data, err := os.ReadFile(p.filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read YAML file %s: %w", p.filepath, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML file %s: %w", p.filepath, err)
+	}
+
+	var groups []*types.Group
+	if len(root.Content) > 0 && root.Content[0].Kind == yaml.SequenceNode {
+		if err := root.Decode(&groups); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML file %s: %w", p.filepath, err)
+		}
+	} else {
+		var document yamlDocument
+		if err := root.Decode(&document); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML file %s: %w", p.filepath, err)
+		}
+		groups = document.Groups
+	}
+
+	totalHosts := 0
+	for _, group := range groups {
+		totalHosts += len(group.AllHosts())
+	}
+
+	if totalHosts == 0 {
+		return nil, fmt.Errorf("no hosts found in YAML file %s", p.filepath)
+	}
+
+	return groups, nil
+}
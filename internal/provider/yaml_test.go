@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+func TestExampleFilesShareSchema(t *testing.T) {
+	dir := filepath.Join("..", "..", "example-provider-data")
+
+	want, err := NewJSONProvider("json", filepath.Join(dir, "hosts.json")).GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, provider := range []Provider{
+		NewYAMLProvider("yaml", filepath.Join(dir, "hosts.yaml")),
+		NewTOMLProvider("toml", filepath.Join(dir, "hosts.toml")),
+	} {
+		got, err := provider.GetGroups(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			t.Errorf("%s groups differ from hosts.json:\n got %s\nwant %s", provider.Name(), gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestYAMLProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []*types.Group
+	}{
+		{
+			name: "anchors in a groups mapping",
+			content: `
+defaults:
+  web: &web
+    user: deploy
+    port: 2222
+    tags: [web]
+    ssh_options: [ForwardAgent=yes]
+
+groups:
+  - name: web
+    transport: mosh
+    hosts:
+      - <<: *web
+        name: web-01
+        hostname: web01.example.com
+      - <<: *web
+        name: web-02
+        hostname: web02.example.com
+        port: 22
+    subgroups:
+      - name: canary
+        hosts:
+          - {<<: *web, name: canary-01, hostname: canary01.example.com, identity_file: ~/.ssh/canary}
+`,
+			want: []*types.Group{{
+				Name:      "web",
+				Transport: "mosh",
+				Hosts: []*types.Host{
+					{Name: "web-01", Hostname: "web01.example.com", User: "deploy", Port: 2222, Tags: []string{"web"}, SSHOptions: []string{"ForwardAgent=yes"}},
+					{Name: "web-02", Hostname: "web02.example.com", User: "deploy", Port: 22, Tags: []string{"web"}, SSHOptions: []string{"ForwardAgent=yes"}},
+				},
+				SubGroups: []*types.Group{{
+					Name: "canary",
+					Hosts: []*types.Host{
+						{Name: "canary-01", Hostname: "canary01.example.com", User: "deploy", Port: 2222, Tags: []string{"web"}, SSHOptions: []string{"ForwardAgent=yes"}, IdentityFile: "~/.ssh/canary"},
+					},
+				}},
+			}},
+		},
+		{
+			name: "list of groups",
+			content: `
+- name: db
+  hosts:
+    - name: db-01
+      hostname: 10.0.0.5
+      inactive: true
+`,
+			want: []*types.Group{{
+				Name:  "db",
+				Hosts: []*types.Host{{Name: "db-01", Hostname: "10.0.0.5", Inactive: true}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeTestFiles(t, map[string]string{"hosts.yaml": test.content})
+			got, err := NewYAMLProvider("yaml", filepath.Join(path, "hosts.yaml")).GetGroups(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(test.want)
+				t.Errorf("groups = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestTOMLProvider(t *testing.T) {
+	path := writeTestFiles(t, map[string]string{"hosts.toml": `
+[[groups]]
+name = "web"
+transport = "mosh"
+
+  [[groups.hosts]]
+  name = "web-01"
+  hostname = "web01.example.com"
+  user = "deploy"
+  port = 2222
+  tags = ["web"]
+  ssh_options = ["ForwardAgent=yes"]
+
+  [[groups.subgroups]]
+  name = "canary"
+
+    [[groups.subgroups.hosts]]
+    name = "canary-01"
+    hostname = "canary01.example.com"
+    identity_file = "~/.ssh/canary"
+`})
+
+	got, err := NewTOMLProvider("toml", filepath.Join(path, "hosts.toml")).GetGroups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*types.Group{{
+		Name:      "web",
+		Transport: "mosh",
+		Hosts: []*types.Host{
+			{Name: "web-01", Hostname: "web01.example.com", User: "deploy", Port: 2222, Tags: []string{"web"}, SSHOptions: []string{"ForwardAgent=yes"}},
+		},
+		SubGroups: []*types.Group{{
+			Name:  "canary",
+			Hosts: []*types.Host{{Name: "canary-01", Hostname: "canary01.example.com", IdentityFile: "~/.ssh/canary"}},
+		}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("groups = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestStructuredFileErrors(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"empty.yaml":   "groups: []\n",
+		"broken.yaml":  "groups: [\n",
+		"unknown.yaml": "- name: web\n  hosts: {name: web-01}\n",
+		"empty.toml":   "title = \"nothing here\"\n",
+		"broken.toml":  "[[groups]\n",
+	})
+
+	tests := []struct {
+		provider Provider
+		want     string
+	}{
+		{NewYAMLProvider("yaml", filepath.Join(dir, "empty.yaml")), "no hosts found in YAML file"},
+		{NewYAMLProvider("yaml", filepath.Join(dir, "broken.yaml")), "failed to parse YAML file"},
+		{NewYAMLProvider("yaml", filepath.Join(dir, "unknown.yaml")), "failed to parse YAML file"},
+		{NewYAMLProvider("yaml", filepath.Join(dir, "missing.yaml")), "failed to read YAML file"},
+		{NewTOMLProvider("toml", filepath.Join(dir, "empty.toml")), "no hosts found in TOML file"},
+		{NewTOMLProvider("toml", filepath.Join(dir, "broken.toml")), "failed to parse TOML file"},
+		{NewTOMLProvider("toml", filepath.Join(dir, "missing.toml")), "failed to read TOML file"},
+	}
+
+	for _, test := range tests {
+		_, err := test.provider.GetGroups(context.Background())
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.provider.Name(), err, test.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type ExecResult struct {
+	ExitCode int
+	Signal   string
+}
+
+func (r ExecResult) Err() error {
+	if r.Signal != "" {
+		return fmt.Errorf("command killed by signal %s", r.Signal)
+	}
+	if r.ExitCode != 0 {
+		return fmt.Errorf("command exited with status %d", r.ExitCode)
+	}
+	return nil
+}
+
+type Executor interface {
+	Run(ctx context.Context, host *types.Host, username, command string, stdout, stderr io.Writer) (ExecResult, error)
+	Close() error
+}
+
+func NewExecutor(kind string) (Executor, error) {
+	switch kind {
+	case "", "external":
+		return ExternalExecutor{}, nil
+	case "native":
+		return NewNativeExecutor(), nil
+	default:
+		return nil, fmt.Errorf("unknown executor %q (expected external or native)", kind)
+	}
+}
+
+type ExternalExecutor struct{}
+
+func (ExternalExecutor) Run(ctx context.Context, host *types.Host, username, command string, stdout, stderr io.Writer) (ExecResult, error) {
+	transport, err := TransportFor(host)
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
+
+	cmd, err := transport.Command(ctx, host, username, command)
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = 500 * time.Millisecond
+
+	err = cmd.Run()
+	if err == nil {
+		return ExecResult{}, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		result := ExecResult{ExitCode: exitErr.ExitCode()}
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			result.Signal = signalName(status.Signal())
+		}
+		return result, nil
+	}
+	if ctx.Err() != nil {
+		return ExecResult{ExitCode: -1}, ctx.Err()
+	}
+	return ExecResult{ExitCode: -1}, fmt.Errorf("%s command failed: %w", cmd.Args[0], err)
+}
+
+func (ExternalExecutor) Close() error {
+	return nil
+}
+
+var signalNames = map[syscall.Signal]string{
+	syscall.SIGHUP:  "HUP",
+	syscall.SIGINT:  "INT",
+	syscall.SIGQUIT: "QUIT",
+	syscall.SIGABRT: "ABRT",
+	syscall.SIGKILL: "KILL",
+	syscall.SIGSEGV: "SEGV",
+	syscall.SIGPIPE: "PIPE",
+	syscall.SIGTERM: "TERM",
+}
+
+func signalName(signal syscall.Signal) string {
+	if name, ok := signalNames[signal]; ok {
+		return name
+	}
+	return signal.String()
+}
@@ -0,0 +1,227 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type KnownStatus int
+
+const (
+	KnownHostUnknown KnownStatus = iota
+	KnownHostMatch
+	KnownHostMismatch
+	KnownHostRevoked
+)
+
+func (s KnownStatus) String() string {
+	switch s {
+	case KnownHostMatch:
+		return "known"
+	case KnownHostMismatch:
+		return "MISMATCH"
+	case KnownHostRevoked:
+		return "REVOKED"
+	default:
+		return "not in known_hosts"
+	}
+}
+
+type HostKeyInfo struct {
+	Type        string
+	Fingerprint string
+	Known       KnownStatus
+}
+
+type ScanResult struct {
+	Banner    string
+	Keys      []HostKeyInfo
+	ScannedAt time.Time
+}
+
+var scanKeyAlgorithms = []string{
+	gossh.KeyAlgoED25519,
+	gossh.KeyAlgoECDSA256,
+	gossh.KeyAlgoECDSA384,
+	gossh.KeyAlgoECDSA521,
+	gossh.KeyAlgoRSASHA512,
+}
+
+var errKeyCaptured = errors.New("host key captured")
+
+func ScanHost(ctx context.Context, host *types.Host, timeout time.Duration) (*ScanResult, error) {
+	port := host.Port
+	if port <= 0 {
+		port = 22
+	}
+	address := net.JoinHostPort(host.Hostname, strconv.Itoa(port))
+
+	checkKnown := loadKnownHosts()
+
+	result := &ScanResult{ScannedAt: time.Now()}
+	var lastErr error
+	for _, algorithm := range scanKeyAlgorithms {
+		banner, key, remote, err := fetchHostKey(ctx, address, algorithm, timeout)
+		if banner != "" && result.Banner == "" {
+			result.Banner = banner
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		result.Keys = append(result.Keys, HostKeyInfo{
+			Type:        key.Type(),
+			Fingerprint: gossh.FingerprintSHA256(key),
+			Known:       knownStatus(checkKnown, address, remote, key),
+		})
+	}
+
+	if len(result.Keys) == 0 && result.Banner == "" && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return result, nil
+}
+
+func fetchHostKey(ctx context.Context, address, algorithm string, timeout time.Duration) (string, gossh.PublicKey, net.Addr, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return "", nil, nil, err
+	}
+
+	recorder := &bannerRecorder{Conn: conn}
+	var captured gossh.PublicKey
+	config := &gossh.ClientConfig{
+		User:              "lssh-keyscan",
+		HostKeyAlgorithms: []string{algorithm},
+		HostKeyCallback: func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+			captured = key
+			return errKeyCaptured
+		},
+		Timeout: timeout,
+	}
+
+	_, _, _, err = gossh.NewClientConn(recorder, address, config)
+	if captured != nil {
+		return recorder.Banner(), captured, conn.RemoteAddr(), nil
+	}
+	if err == nil {
+		err = fmt.Errorf("no host key received")
+	}
+	return recorder.Banner(), nil, nil, err
+}
+
+func loadKnownHosts() gossh.HostKeyCallback {
+	var files []string
+	for _, file := range DefaultKnownHostsFiles() {
+		if _, err := os.Stat(file); err == nil {
+			files = append(files, file)
+		}
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	callback, err := knownhosts.New(files...)
+	if err != nil {
+		return nil
+	}
+	return callback
+}
+
+func DefaultKnownHostsFiles() []string {
+	files := []string{"/etc/ssh/ssh_known_hosts"}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		files = append([]string{filepath.Join(homeDir, ".ssh", "known_hosts")}, files...)
+	}
+	return files
+}
+
+func knownStatus(callback gossh.HostKeyCallback, address string, remote net.Addr, key gossh.PublicKey) KnownStatus {
+	if callback == nil {
+		return KnownHostUnknown
+	}
+
+	err := callback(address, remote, key)
+	if err == nil {
+		return KnownHostMatch
+	}
+
+	var revokedErr *knownhosts.RevokedError
+	if errors.As(err, &revokedErr) {
+		return KnownHostRevoked
+	}
+
+	var keyErr *knownhosts.KeyError
+	if errors.As(err, &keyErr) && len(keyErr.Want) > 0 {
+		for _, want := range keyErr.Want {
+			if want.Key.Type() == key.Type() {
+				return KnownHostMismatch
+			}
+		}
+	}
+
+	return KnownHostUnknown
+}
+
+type bannerRecorder struct {
+	net.Conn
+
+	mu     sync.Mutex
+	buffer bytes.Buffer
+	done   bool
+}
+
+func (r *bannerRecorder) Read(p []byte) (int, error) {
+	n, err := r.Conn.Read(p)
+
+	r.mu.Lock()
+	if !r.done && n > 0 {
+		r.buffer.Write(p[:n])
+		if findBanner(r.buffer.String()) != "" || r.buffer.Len() > 4096 {
+			r.done = true
+		}
+	}
+	r.mu.Unlock()
+
+	return n, err
+}
+
+func (r *bannerRecorder) Banner() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return findBanner(r.buffer.String())
+}
+
+func findBanner(data string) string {
+	lines := strings.Split(data, "\n")
+	for _, line := range lines[:len(lines)-1] {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "SSH-") {
+			return line
+		}
+	}
+	return ""
+}
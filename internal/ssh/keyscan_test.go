@@ -0,0 +1,147 @@
+package ssh
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func startKeyscanServer(t *testing.T, version string) *testServer {
+	t.Helper()
+
+	hostKey, _ := newTestSigner(t)
+	config := &gossh.ServerConfig{NoClientAuth: true, ServerVersion: version}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config)
+		}
+	}()
+
+	return &testServer{address: listener.Addr().String(), hostKey: hostKey}
+}
+
+func scanTestServer(t *testing.T, server *testServer, knownHosts string) *ScanResult {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".ssh", "known_hosts"), []byte(knownHosts), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	hostname, port, err := net.SplitHostPort(server.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNumber, _ := strconv.Atoi(port)
+
+	result, err := ScanHost(context.Background(), &types.Host{Name: "test", Hostname: hostname, Port: portNumber}, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestScanHostKnownStatus(t *testing.T) {
+	server := startKeyscanServer(t, "")
+	otherKey, _ := newTestSigner(t)
+	pattern := knownhosts.Normalize(server.address)
+
+	tests := []struct {
+		name       string
+		knownHosts string
+		want       KnownStatus
+	}{
+		{"not in known_hosts", "", KnownHostUnknown},
+		{"other host only", knownhosts.Line([]string{"example.com"}, server.hostKey.PublicKey()) + "\n", KnownHostUnknown},
+		{"match", knownhosts.Line([]string{pattern}, server.hostKey.PublicKey()) + "\n", KnownHostMatch},
+		{"hashed match", knownhosts.Line([]string{knownhosts.HashHostname(pattern)}, server.hostKey.PublicKey()) + "\n", KnownHostMatch},
+		{"mismatch", knownhosts.Line([]string{pattern}, otherKey.PublicKey()) + "\n", KnownHostMismatch},
+		{"revoked", "@revoked * " + strings.TrimSpace(string(gossh.MarshalAuthorizedKey(server.hostKey.PublicKey()))) + "\n", KnownHostRevoked},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := scanTestServer(t, server, test.knownHosts)
+
+			if len(result.Keys) != 1 {
+				t.Fatalf("keys = %+v, want the server's ed25519 key", result.Keys)
+			}
+			key := result.Keys[0]
+			if key.Type != gossh.KeyAlgoED25519 || key.Fingerprint != gossh.FingerprintSHA256(server.hostKey.PublicKey()) {
+				t.Errorf("key = %+v, want the server's ed25519 key", key)
+			}
+			if key.Known != test.want {
+				t.Errorf("known = %s, want %s", key.Known, test.want)
+			}
+		})
+	}
+}
+
+func TestScanHostBanner(t *testing.T) {
+	server := startKeyscanServer(t, "SSH-2.0-OpenSSH_9.6p1 Ubuntu-3ubuntu13")
+
+	result := scanTestServer(t, server, "")
+	if result.Banner != "SSH-2.0-OpenSSH_9.6p1 Ubuntu-3ubuntu13" {
+		t.Errorf("banner = %q", result.Banner)
+	}
+	if result.ScannedAt.IsZero() {
+		t.Error("scan time was not recorded")
+	}
+}
+
+func TestScanHostUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().(*net.TCPAddr)
+	listener.Close()
+
+	_, err = ScanHost(context.Background(), &types.Host{Hostname: "127.0.0.1", Port: address.Port}, time.Second)
+	if err == nil {
+		t.Fatal("scanning a closed port succeeded")
+	}
+}
+
+func TestFindBanner(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"SSH-2.0-OpenSSH_9.6\r\n", "SSH-2.0-OpenSSH_9.6"},
+		{"Welcome to the lab\r\nSSH-2.0-dropbear_2022.83\r\n", "SSH-2.0-dropbear_2022.83"},
+		{"SSH-2.0-partial", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := findBanner(test.data); got != test.want {
+			t.Errorf("findBanner(%q) = %q, want %q", test.data, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,591 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type NativeExecutor struct {
+	ConnectTimeout time.Duration
+
+	fallback Executor
+	resolve  func(ctx context.Context, host *types.Host, username string) (nativeConfig, error)
+
+	mu      sync.Mutex
+	clients map[string]*nativeClient
+	configs map[string]nativeConfig
+	agent   agent.ExtendedAgent
+	agentMu sync.Once
+}
+
+type nativeClient struct {
+	ready  chan struct{}
+	client *gossh.Client
+	err    error
+}
+
+var defaultIdentityFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+func NewNativeExecutor() *NativeExecutor {
+	return &NativeExecutor{
+		ConnectTimeout: 10 * time.Second,
+		fallback:       ExternalExecutor{},
+		resolve:        resolveSSHConfig,
+		clients:        make(map[string]*nativeClient),
+		configs:        make(map[string]nativeConfig),
+	}
+}
+
+func (e *NativeExecutor) Run(ctx context.Context, host *types.Host, username, command string, stdout, stderr io.Writer) (ExecResult, error) {
+	if !UsesSSH(host) {
+		return e.fallback.Run(ctx, host, username, command, stdout, stderr)
+	}
+
+	username, err := resolveUser(host, username)
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
+
+	session, err := e.session(ctx, host, username)
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
+	defer session.Close()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(command)
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		_ = session.Signal(gossh.SIGKILL)
+		_ = session.Close()
+		<-done
+		return ExecResult{ExitCode: -1}, ctx.Err()
+	}
+
+	if err == nil {
+		return ExecResult{}, nil
+	}
+
+	var exitErr *gossh.ExitError
+	if errors.As(err, &exitErr) {
+		return ExecResult{ExitCode: exitErr.ExitStatus(), Signal: exitErr.Signal()}, nil
+	}
+
+	var missingErr *gossh.ExitMissingError
+	if errors.As(err, &missingErr) {
+		return ExecResult{ExitCode: -1}, fmt.Errorf("connection to %s closed without an exit status", host.Name)
+	}
+
+	return ExecResult{ExitCode: -1}, fmt.Errorf("ssh command failed on %s: %w", host.Name, err)
+}
+
+func (e *NativeExecutor) Close() error {
+	e.mu.Lock()
+	clients := e.clients
+	e.clients = make(map[string]*nativeClient)
+	e.mu.Unlock()
+
+	var firstErr error
+	for _, entry := range clients {
+		<-entry.ready
+		if entry.client == nil {
+			continue
+		}
+		if err := entry.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (e *NativeExecutor) session(ctx context.Context, host *types.Host, username string) (*gossh.Session, error) {
+	client, err := e.client(ctx, host, username)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := client.NewSession()
+	if err == nil {
+		return session, nil
+	}
+
+	e.forget(host, username, client)
+	client, err = e.client(ctx, host, username)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err = client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session on %s: %w", host.Name, err)
+	}
+	return session, nil
+}
+
+func (e *NativeExecutor) client(ctx context.Context, host *types.Host, username string) (*gossh.Client, error) {
+	key := username + "@" + nativeAddress(host)
+
+	e.mu.Lock()
+	entry, exists := e.clients[key]
+	if exists {
+		select {
+		case <-entry.ready:
+			if entry.err != nil {
+				exists = false
+			}
+		default:
+		}
+	}
+	if !exists {
+		entry = &nativeClient{ready: make(chan struct{})}
+		e.clients[key] = entry
+		e.mu.Unlock()
+
+		entry.client, entry.err = e.dial(ctx, host, username)
+		close(entry.ready)
+		return entry.client, entry.err
+	}
+	e.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+		return entry.client, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (e *NativeExecutor) forget(host *types.Host, username string, client *gossh.Client) {
+	key := username + "@" + nativeAddress(host)
+
+	e.mu.Lock()
+	if entry, exists := e.clients[key]; exists && entry.client == client {
+		delete(e.clients, key)
+	}
+	e.mu.Unlock()
+
+	_ = client.Close()
+}
+
+func (e *NativeExecutor) resolveConfig(ctx context.Context, host *types.Host, username string) (nativeConfig, error) {
+	key := strings.Join(append(hostArgs(host), username+"@"+host.Hostname), "\x00")
+
+	e.mu.Lock()
+	resolved, exists := e.configs[key]
+	e.mu.Unlock()
+	if exists {
+		return resolved, nil
+	}
+
+	resolved, err := e.resolve(ctx, host, username)
+	if err != nil {
+		return nativeConfig{}, err
+	}
+
+	e.mu.Lock()
+	e.configs[key] = resolved
+	e.mu.Unlock()
+	return resolved, nil
+}
+
+func (e *NativeExecutor) dial(ctx context.Context, host *types.Host, username string) (*gossh.Client, error) {
+	resolved, err := e.resolveConfig(ctx, host, username)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.proxy != "" {
+		return nil, fmt.Errorf("%s is reached through %s, which the native executor does not support (use the external executor)", host.Name, resolved.proxy)
+	}
+
+	address := net.JoinHostPort(resolved.hostname, strconv.Itoa(resolved.port))
+	callback, algorithms, err := hostKeyCallback(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &gossh.ClientConfig{
+		User:              resolved.user,
+		Auth:              e.authMethods(resolved),
+		HostKeyCallback:   callback,
+		HostKeyAlgorithms: algorithms,
+		Timeout:           e.ConnectTimeout,
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, e.ConnectTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(dialCtx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+
+	if deadline, ok := dialCtx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	clientConn, channels, requests, err := gossh.NewClientConn(conn, resolved.knownHostsAddress(), config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh handshake with %s failed: %w", address, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	return gossh.NewClient(clientConn, channels, requests), nil
+}
+
+func (e *NativeExecutor) authMethods(resolved nativeConfig) []gossh.AuthMethod {
+	var fileSigners []gossh.Signer
+	for _, file := range resolved.identityFiles {
+		if signer := loadIdentity(file); signer != nil {
+			fileSigners = append(fileSigners, signer)
+		}
+	}
+
+	return []gossh.AuthMethod{gossh.PublicKeysCallback(func() ([]gossh.Signer, error) {
+		signers := append([]gossh.Signer{}, fileSigners...)
+		if resolved.identitiesOnly {
+			return signers, nil
+		}
+		if agentClient := e.sshAgent(); agentClient != nil {
+			if agentSigners, err := agentClient.Signers(); err == nil {
+				signers = append(agentSigners, signers...)
+			}
+		}
+		return signers, nil
+	})}
+}
+
+func (e *NativeExecutor) sshAgent() agent.ExtendedAgent {
+	e.agentMu.Do(func() {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return
+		}
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return
+		}
+		e.agent = agent.NewClient(conn)
+	})
+	return e.agent
+}
+
+func loadIdentity(file string) gossh.Signer {
+	data, err := os.ReadFile(expandHome(file))
+	if err != nil {
+		return nil
+	}
+
+	signer, err := gossh.ParsePrivateKey(data)
+	if err != nil {
+		return nil
+	}
+	return signer
+}
+
+var knownHostsMu sync.Mutex
+
+func hostKeyCallback(resolved nativeConfig) (gossh.HostKeyCallback, []string, error) {
+	known, err := readKnownHosts(resolved.knownHostsFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	callback := func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+		err := checkKnownHost(known, hostname, remote, key)
+		if !isUnknownHost(err) {
+			return err
+		}
+		if resolved.strictHostKeyChecking != "no" && resolved.strictHostKeyChecking != "accept-new" {
+			return fmt.Errorf("host key for %s is not in known_hosts (connect once with ssh or set StrictHostKeyChecking=accept-new)", hostname)
+		}
+
+		knownHostsMu.Lock()
+		defer knownHostsMu.Unlock()
+
+		current, err := readKnownHosts(resolved.knownHostsFiles)
+		if err != nil {
+			return err
+		}
+		if err := checkKnownHost(current, hostname, remote, key); !isUnknownHost(err) {
+			return err
+		}
+		return recordHostKey(resolved, hostname, key)
+	}
+
+	return callback, knownHostAlgorithms(known, resolved.knownHostsAddress()), nil
+}
+
+func readKnownHosts(files []string) (gossh.HostKeyCallback, error) {
+	var existing []string
+	for _, file := range files {
+		file = expandHome(file)
+		if _, err := os.Stat(file); err == nil {
+			existing = append(existing, file)
+		}
+	}
+	if len(existing) == 0 {
+		return nil, nil
+	}
+
+	known, err := knownhosts.New(existing...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+	return known, nil
+}
+
+func checkKnownHost(known gossh.HostKeyCallback, hostname string, remote net.Addr, key gossh.PublicKey) error {
+	if known == nil {
+		return &knownhosts.KeyError{}
+	}
+	return known(hostname, remote, key)
+}
+
+func isUnknownHost(err error) bool {
+	var keyErr *knownhosts.KeyError
+	return errors.As(err, &keyErr) && len(keyErr.Want) == 0
+}
+
+func recordHostKey(resolved nativeConfig, hostname string, key gossh.PublicKey) error {
+	if len(resolved.userKnownHostsFiles) == 0 {
+		return fmt.Errorf("cannot record the host key for %s: no UserKnownHostsFile is set", hostname)
+	}
+	file := expandHome(resolved.userKnownHostsFiles[0])
+
+	address := knownhosts.Normalize(hostname)
+	if resolved.hashKnownHosts {
+		address = knownhosts.HashHostname(address)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("failed to record the host key for %s: %w", hostname, err)
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to record the host key for %s: %w", hostname, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(knownhosts.Line([]string{address}, key) + "\n"); err != nil {
+		return fmt.Errorf("failed to record the host key for %s: %w", hostname, err)
+	}
+	return nil
+}
+
+func knownHostAlgorithms(known gossh.HostKeyCallback, address string) []string {
+	if known == nil {
+		return nil
+	}
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil
+	}
+	probe, err := gossh.NewSignerFromKey(private)
+	if err != nil {
+		return nil
+	}
+
+	var keyErr *knownhosts.KeyError
+	err = known(address, &net.TCPAddr{IP: net.IPv4zero}, probe.PublicKey())
+	if !errors.As(err, &keyErr) || len(keyErr.Want) == 0 {
+		return nil
+	}
+
+	var algorithms []string
+	seen := make(map[string]bool)
+	for _, want := range keyErr.Want {
+		keyType := want.Key.Type()
+		candidates := []string{keyType}
+		if keyType == gossh.KeyAlgoRSA {
+			candidates = []string{gossh.KeyAlgoRSASHA512, gossh.KeyAlgoRSASHA256, gossh.KeyAlgoRSA}
+		}
+		for _, algorithm := range candidates {
+			if !seen[algorithm] {
+				seen[algorithm] = true
+				algorithms = append(algorithms, algorithm)
+			}
+		}
+	}
+	return algorithms
+}
+
+func parseSSHOptions(options []string) map[string][]string {
+	parsed := make(map[string][]string)
+	for _, option := range options {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			key, value, ok = strings.Cut(strings.TrimSpace(option), " ")
+			if !ok {
+				continue
+			}
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		parsed[key] = append(parsed[key], strings.Trim(strings.TrimSpace(value), `"`))
+	}
+	return parsed
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, path[2:])
+		}
+	}
+	return path
+}
+
+func firstOption(options map[string][]string, key string) string {
+	if values := options[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+type nativeConfig struct {
+	hostname              string
+	port                  int
+	user                  string
+	hostKeyAlias          string
+	identityFiles         []string
+	identitiesOnly        bool
+	strictHostKeyChecking string
+	userKnownHostsFiles   []string
+	knownHostsFiles       []string
+	hashKnownHosts        bool
+	proxy                 string
+}
+
+func (c nativeConfig) knownHostsAddress() string {
+	name := c.hostname
+	if c.hostKeyAlias != "" {
+		name = c.hostKeyAlias
+	}
+	return net.JoinHostPort(name, strconv.Itoa(c.port))
+}
+
+func resolveSSHConfig(ctx context.Context, host *types.Host, username string) (nativeConfig, error) {
+	args := append([]string{"-G"}, hostArgs(host)...)
+	cmd := exec.CommandContext(ctx, "ssh", append(args, username+"@"+host.Hostname)...)
+
+	output, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return hostSSHConfig(host, username), nil
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			err = fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nativeConfig{}, fmt.Errorf("failed to resolve the ssh configuration for %s: %w", host.Name, err)
+	}
+
+	options := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok {
+			key = strings.ToLower(key)
+			options[key] = append(options[key], value)
+		}
+	}
+	return newNativeConfig(options), nil
+}
+
+func hostSSHConfig(host *types.Host, username string) nativeConfig {
+	options := parseSSHOptions(host.SSHOptions)
+	options["hostname"] = []string{host.Hostname}
+	options["user"] = []string{username}
+	if host.Port > 0 {
+		options["port"] = []string{strconv.Itoa(host.Port)}
+	}
+	if host.IdentityFile != "" {
+		options["identityfile"] = append([]string{host.IdentityFile}, options["identityfile"]...)
+	}
+	if len(options["identityfile"]) == 0 {
+		for _, name := range defaultIdentityFiles {
+			options["identityfile"] = append(options["identityfile"], "~/.ssh/"+name)
+		}
+	}
+	if len(options["userknownhostsfile"]) == 0 {
+		options["userknownhostsfile"] = []string{"~/.ssh/known_hosts"}
+	}
+	if len(options["globalknownhostsfile"]) == 0 {
+		options["globalknownhostsfile"] = []string{"/etc/ssh/ssh_known_hosts"}
+	}
+	return newNativeConfig(options)
+}
+
+func newNativeConfig(options map[string][]string) nativeConfig {
+	config := nativeConfig{
+		hostname:       firstOption(options, "hostname"),
+		user:           firstOption(options, "user"),
+		port:           22,
+		hostKeyAlias:   firstOption(options, "hostkeyalias"),
+		identityFiles:  options["identityfile"],
+		identitiesOnly: isYes(firstOption(options, "identitiesonly")),
+		hashKnownHosts: isYes(firstOption(options, "hashknownhosts")),
+	}
+	if port, err := strconv.Atoi(firstOption(options, "port")); err == nil && port > 0 {
+		config.port = port
+	}
+
+	switch strict := strings.ToLower(firstOption(options, "stricthostkeychecking")); strict {
+	case "no", "off", "false":
+		config.strictHostKeyChecking = "no"
+	case "accept-new":
+		config.strictHostKeyChecking = "accept-new"
+	case "ask":
+		config.strictHostKeyChecking = "ask"
+	default:
+		config.strictHostKeyChecking = "yes"
+	}
+
+	config.userKnownHostsFiles = strings.Fields(strings.Join(options["userknownhostsfile"], " "))
+	config.knownHostsFiles = append(append([]string{}, config.userKnownHostsFiles...), strings.Fields(strings.Join(options["globalknownhostsfile"], " "))...)
+
+	for _, key := range []string{"proxyjump", "proxycommand"} {
+		if value := firstOption(options, key); value != "" && !strings.EqualFold(value, "none") {
+			config.proxy = map[string]string{"proxyjump": "ProxyJump", "proxycommand": "ProxyCommand"}[key] + " " + value
+			break
+		}
+	}
+
+	return config
+}
+
+func isYes(value string) bool {
+	return strings.EqualFold(value, "yes") || strings.EqualFold(value, "true")
+}
+
+func nativeAddress(host *types.Host) string {
+	port := host.Port
+	if port <= 0 {
+		port = 22
+	}
+	return net.JoinHostPort(host.Hostname, strconv.Itoa(port))
+}
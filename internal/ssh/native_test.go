@@ -0,0 +1,448 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type testServer struct {
+	address string
+	hostKey gossh.Signer
+}
+
+func newTestSigner(t *testing.T) (gossh.Signer, ed25519.PrivateKey) {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, private
+}
+
+func startTestServer(t *testing.T, clientKey gossh.PublicKey) *testServer {
+	t.Helper()
+
+	hostKey, _ := newTestSigner(t)
+	config := &gossh.ServerConfig{
+		PublicKeyCallback: func(conn gossh.ConnMetadata, key gossh.PublicKey) (*gossh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config)
+		}
+	}()
+
+	return &testServer{address: listener.Addr().String(), hostKey: hostKey}
+}
+
+func serveTestConn(conn net.Conn, config *gossh.ServerConfig) {
+	_, channels, requests, err := gossh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go gossh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(gossh.UnknownChannelType, "unsupported")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go serveTestSession(channel, channelRequests)
+	}
+}
+
+func serveTestSession(channel gossh.Channel, requests <-chan *gossh.Request) {
+	defer channel.Close()
+
+	for request := range requests {
+		if request.Type != "exec" {
+			_ = request.Reply(false, nil)
+			continue
+		}
+
+		var payload struct{ Command string }
+		if err := gossh.Unmarshal(request.Payload, &payload); err != nil {
+			_ = request.Reply(false, nil)
+			return
+		}
+		_ = request.Reply(true, nil)
+
+		fmt.Fprintf(channel, "out: %s\n", payload.Command)
+		fmt.Fprintf(channel.Stderr(), "err: %s\n", payload.Command)
+
+		fields := strings.Fields(payload.Command)
+		switch fields[0] {
+		case "exit":
+			status, _ := strconv.Atoi(fields[1])
+			_, _ = channel.SendRequest("exit-status", false, gossh.Marshal(struct{ Status uint32 }{uint32(status)}))
+		case "kill":
+			_, _ = channel.SendRequest("exit-signal", false, gossh.Marshal(struct {
+				Signal     string
+				CoreDumped bool
+				Error      string
+				Lang       string
+			}{Signal: fields[1]}))
+		case "hang":
+			for request := range requests {
+				if request.Type == "signal" {
+					break
+				}
+			}
+		}
+		return
+	}
+}
+
+type nativeTestSetup struct {
+	server     *testServer
+	identity   string
+	knownHosts string
+}
+
+func newNativeTestSetup(t *testing.T) *nativeTestSetup {
+	t.Helper()
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	clientKey, private := newTestSigner(t)
+	server := startTestServer(t, clientKey.PublicKey())
+
+	dir := t.TempDir()
+	block, err := gossh.MarshalPrivateKey(private, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(identity, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return &nativeTestSetup{server: server, identity: identity, knownHosts: filepath.Join(dir, "known_hosts")}
+}
+
+func (s *nativeTestSetup) trustHostKey(t *testing.T, key gossh.PublicKey) {
+	t.Helper()
+
+	line := knownhosts.Line([]string{s.server.address}, key) + "\n"
+	if err := os.WriteFile(s.knownHosts, []byte(line), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *nativeTestSetup) executor(strict string, extra map[string][]string) *NativeExecutor {
+	executor := NewNativeExecutor()
+	executor.ConnectTimeout = 5 * time.Second
+	executor.resolve = func(ctx context.Context, host *types.Host, username string) (nativeConfig, error) {
+		hostname, port, _ := net.SplitHostPort(s.server.address)
+		options := map[string][]string{
+			"hostname":              {hostname},
+			"port":                  {port},
+			"user":                  {username},
+			"identityfile":          {s.identity},
+			"userknownhostsfile":    {s.knownHosts},
+			"stricthostkeychecking": {strict},
+		}
+		for key, values := range extra {
+			options[key] = values
+		}
+		return newNativeConfig(options), nil
+	}
+	return executor
+}
+
+func runNative(executor *NativeExecutor, command string) (ExecResult, string, string, error) {
+	var stdout, stderr bytes.Buffer
+	host := &types.Host{Name: "test", Hostname: "test.invalid", User: "tester"}
+	result, err := executor.Run(context.Background(), host, "", command, &stdout, &stderr)
+	return result, stdout.String(), stderr.String(), err
+}
+
+func TestNativeExecutorResults(t *testing.T) {
+	setup := newNativeTestSetup(t)
+	setup.trustHostKey(t, setup.server.hostKey.PublicKey())
+
+	executor := setup.executor("yes", nil)
+	defer executor.Close()
+
+	tests := []struct {
+		command string
+		want    ExecResult
+	}{
+		{"exit 0", ExecResult{ExitCode: 0}},
+		{"exit 3", ExecResult{ExitCode: 3}},
+		{"kill KILL", ExecResult{ExitCode: -1, Signal: "KILL"}},
+		{"kill TERM", ExecResult{ExitCode: -1, Signal: "TERM"}},
+	}
+
+	for _, test := range tests {
+		result, stdout, stderr, err := runNative(executor, test.command)
+		if err != nil {
+			t.Fatalf("%s: %v", test.command, err)
+		}
+		if result.ExitCode != test.want.ExitCode && test.want.Signal == "" {
+			t.Errorf("%s: exit code = %d, want %d", test.command, result.ExitCode, test.want.ExitCode)
+		}
+		if result.Signal != test.want.Signal {
+			t.Errorf("%s: signal = %q, want %q", test.command, result.Signal, test.want.Signal)
+		}
+		if (result.Err() == nil) != (test.command == "exit 0") {
+			t.Errorf("%s: Err() = %v", test.command, result.Err())
+		}
+		if stdout != "out: "+test.command+"\n" {
+			t.Errorf("%s: stdout = %q", test.command, stdout)
+		}
+		if stderr != "err: "+test.command+"\n" {
+			t.Errorf("%s: stderr = %q", test.command, stderr)
+		}
+	}
+}
+
+func TestNativeExecutorCancel(t *testing.T) {
+	setup := newNativeTestSetup(t)
+	setup.trustHostKey(t, setup.server.hostKey.PublicKey())
+
+	executor := setup.executor("yes", nil)
+	defer executor.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	host := &types.Host{Name: "test", Hostname: "test.invalid", User: "tester"}
+	result, err := executor.Run(ctx, host, "", "hang", &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || ctx.Err() == nil {
+		t.Fatalf("Run returned %v, %v before the context was cancelled", result, err)
+	}
+	if result.ExitCode != -1 {
+		t.Errorf("exit code = %d, want -1", result.ExitCode)
+	}
+}
+
+func TestNativeExecutorHostKeys(t *testing.T) {
+	otherKey, _ := newTestSigner(t)
+
+	tests := []struct {
+		name    string
+		trusted gossh.PublicKey
+		strict  string
+		wantErr string
+	}{
+		{"unknown host refused", nil, "yes", "not in known_hosts"},
+		{"unknown host refused when asking", nil, "ask", "not in known_hosts"},
+		{"changed key refused", otherKey.PublicKey(), "yes", "key mismatch"},
+		{"changed key refused without strict checking", otherKey.PublicKey(), "no", "key mismatch"},
+		{"changed key refused with accept-new", otherKey.PublicKey(), "accept-new", "key mismatch"},
+		{"unknown host accepted", nil, "no", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setup := newNativeTestSetup(t)
+			if test.trusted != nil {
+				setup.trustHostKey(t, test.trusted)
+			}
+
+			executor := setup.executor(test.strict, nil)
+			defer executor.Close()
+
+			_, _, _, err := runNative(executor, "exit 0")
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNativeExecutorAcceptNewRecordsKey(t *testing.T) {
+	for _, hashed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hashed=%v", hashed), func(t *testing.T) {
+			setup := newNativeTestSetup(t)
+			extra := map[string][]string{}
+			if hashed {
+				extra["hashknownhosts"] = []string{"yes"}
+			}
+
+			executor := setup.executor("accept-new", extra)
+			if _, _, _, err := runNative(executor, "exit 0"); err != nil {
+				t.Fatal(err)
+			}
+			executor.Close()
+
+			data, err := os.ReadFile(setup.knownHosts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lines := strings.Count(string(data), "\n"); lines != 1 {
+				t.Fatalf("known_hosts has %d lines, want 1:\n%s", lines, data)
+			}
+			if strings.HasPrefix(string(data), "|1|") != hashed {
+				t.Errorf("known_hosts entry hashed = %v, want %v: %s", !hashed, hashed, data)
+			}
+
+			strict := setup.executor("yes", nil)
+			defer strict.Close()
+			if _, _, _, err := runNative(strict, "exit 0"); err != nil {
+				t.Fatalf("recorded key was not trusted: %v", err)
+			}
+		})
+	}
+}
+
+func TestNativeExecutorRefusesProxies(t *testing.T) {
+	setup := newNativeTestSetup(t)
+	setup.trustHostKey(t, setup.server.hostKey.PublicKey())
+
+	for _, key := range []string{"proxyjump", "proxycommand"} {
+		executor := setup.executor("yes", map[string][]string{key: {"bastion.example.com"}})
+		_, _, _, err := runNative(executor, "exit 0")
+		executor.Close()
+		if err == nil || !strings.Contains(err.Error(), "does not support") {
+			t.Errorf("%s: error = %v, want the proxy to be refused", key, err)
+		}
+	}
+
+	executor := setup.executor("yes", map[string][]string{"proxyjump": {"none"}})
+	defer executor.Close()
+	if _, _, _, err := runNative(executor, "exit 0"); err != nil {
+		t.Errorf("ProxyJump none: %v", err)
+	}
+}
+
+func TestNativeExecutorCachesResolvedConfig(t *testing.T) {
+	setup := newNativeTestSetup(t)
+	setup.trustHostKey(t, setup.server.hostKey.PublicKey())
+
+	executor := setup.executor("yes", nil)
+	defer executor.Close()
+
+	resolve := executor.resolve
+	calls := make(map[string]int)
+	failNext := true
+	executor.resolve = func(ctx context.Context, host *types.Host, username string) (nativeConfig, error) {
+		calls[username+"@"+host.Hostname+":"+strconv.Itoa(host.Port)]++
+		if failNext {
+			failNext = false
+			return nativeConfig{}, fmt.Errorf("ssh -G failed")
+		}
+		return resolve(ctx, host, username)
+	}
+
+	tests := []struct {
+		host     *types.Host
+		username string
+		wantErr  bool
+	}{
+		{&types.Host{Name: "test", Hostname: "test.invalid", User: "tester"}, "", true},
+		{&types.Host{Name: "test", Hostname: "test.invalid", User: "tester"}, "", false},
+		{&types.Host{Name: "test", Hostname: "test.invalid", User: "tester"}, "", false},
+		{&types.Host{Name: "test", Hostname: "test.invalid", User: "tester"}, "admin", false},
+		{&types.Host{Name: "test", Hostname: "test.invalid", User: "tester", Port: 2222}, "", false},
+		{&types.Host{Name: "test", Hostname: "test.invalid", User: "tester", Port: 2222}, "", false},
+	}
+
+	for _, test := range tests {
+		_, err := executor.Run(context.Background(), test.host, test.username, "exit 0", &bytes.Buffer{}, &bytes.Buffer{})
+		if (err != nil) != test.wantErr {
+			t.Fatalf("Run(%s, %q) = %v, wantErr %v", test.host.Hostname, test.username, err, test.wantErr)
+		}
+		executor.Close()
+	}
+
+	want := map[string]int{
+		"tester@test.invalid:0":    2,
+		"admin@test.invalid:0":     1,
+		"tester@test.invalid:2222": 1,
+	}
+	for key, count := range want {
+		if calls[key] != count {
+			t.Errorf("%s resolved %d times, want %d", key, calls[key], count)
+		}
+	}
+}
+
+func TestNewNativeConfig(t *testing.T) {
+	output := `user bob
+hostname 10.0.0.5
+port 2222
+hashknownhosts yes
+identitiesonly yes
+stricthostkeychecking false
+identityfile ~/.ssh/id_work
+identityfile ~/.ssh/id_rsa
+globalknownhostsfile /etc/ssh/ssh_known_hosts /etc/ssh/ssh_known_hosts2
+userknownhostsfile ~/.ssh/known_hosts ~/.ssh/known_hosts2
+proxycommand none
+`
+	options := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		if key, value, ok := strings.Cut(line, " "); ok {
+			options[key] = append(options[key], value)
+		}
+	}
+	config := newNativeConfig(options)
+
+	if config.hostname != "10.0.0.5" || config.port != 2222 || config.user != "bob" {
+		t.Errorf("target = %s@%s:%d", config.user, config.hostname, config.port)
+	}
+	if !config.hashKnownHosts || !config.identitiesOnly {
+		t.Errorf("hashKnownHosts = %v, identitiesOnly = %v", config.hashKnownHosts, config.identitiesOnly)
+	}
+	if config.strictHostKeyChecking != "no" {
+		t.Errorf("strictHostKeyChecking = %q", config.strictHostKeyChecking)
+	}
+	if len(config.identityFiles) != 2 || config.identityFiles[0] != "~/.ssh/id_work" {
+		t.Errorf("identityFiles = %q", config.identityFiles)
+	}
+	if len(config.knownHostsFiles) != 4 || config.userKnownHostsFiles[0] != "~/.ssh/known_hosts" {
+		t.Errorf("knownHostsFiles = %q", config.knownHostsFiles)
+	}
+	if config.proxy != "" {
+		t.Errorf("proxy = %q", config.proxy)
+	}
+}
@@ -0,0 +1,425 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"os/user"
+	"strings"
+	"text/template"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type Transport interface {
+	Interactive(host *types.Host, username string) (*exec.Cmd, error)
+	Command(ctx context.Context, host *types.Host, username, command string) (*exec.Cmd, error)
+}
+
+var transports = map[string]Transport{
+	"ssh":    sshTransport{},
+	"mosh":   moshTransport{},
+	"et":     etTransport{},
+	"telnet": telnetTransport{},
+	"docker": containerTransport{binary: "docker"},
+	"podman": containerTransport{binary: "podman"},
+}
+
+var sshBasedTransports = map[string]bool{"": true, "ssh": true, "mosh": true, "et": true}
+
+func RegisterTemplateTransport(name, interactive, command string) error {
+	if _, exists := transports[name]; exists {
+		return fmt.Errorf("transport %q is already defined", name)
+	}
+	if interactive == "" {
+		return fmt.Errorf("transport %q requires an interactive command template", name)
+	}
+
+	transport := templateTransport{name: name}
+
+	var err error
+	transport.interactive, err = parseTemplateArgs(name, interactive)
+	if err != nil {
+		return fmt.Errorf("invalid interactive template for transport %q: %w", name, err)
+	}
+	if command != "" {
+		transport.command, err = parseTemplateArgs(name, command)
+		if err != nil {
+			return fmt.Errorf("invalid command template for transport %q: %w", name, err)
+		}
+	}
+
+	transports[name] = transport
+	return nil
+}
+
+func TransportFor(host *types.Host) (Transport, error) {
+	name := host.Transport
+	if name == "" {
+		name = "ssh"
+	}
+
+	transport, ok := transports[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown transport %q for host %s", name, host.Name)
+	}
+	return transport, nil
+}
+
+func IsSSH(host *types.Host) bool {
+	return host.Transport == "" || host.Transport == "ssh"
+}
+
+func UsesSSH(host *types.Host) bool {
+	return sshBasedTransports[host.Transport]
+}
+
+func CommandLine(host *types.Host) string {
+	if IsSSH(host) {
+		return host.SSHCommand()
+	}
+
+	transport, err := TransportFor(host)
+	if err != nil {
+		return err.Error()
+	}
+	cmd, err := transport.Interactive(host, "")
+	if err != nil {
+		return err.Error()
+	}
+
+	var args []string
+	for _, arg := range cmd.Args {
+		args = append(args, shellQuote(arg))
+	}
+	return strings.Join(args, " ")
+}
+
+func shellQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+type sshTransport struct{}
+
+func (sshTransport) Interactive(host *types.Host, username string) (*exec.Cmd, error) {
+	target, err := sshTarget(host, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return exec.Command("ssh", append(hostArgs(host), target)...), nil
+}
+
+func (sshTransport) Command(ctx context.Context, host *types.Host, username, command string) (*exec.Cmd, error) {
+	target, err := sshTarget(host, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return exec.CommandContext(ctx, "ssh", append(hostArgs(host), target, command)...), nil
+}
+
+func sshTarget(host *types.Host, username string) (string, error) {
+	username, err := resolveUser(host, username)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s@%s", username, host.Hostname), nil
+}
+
+func resolveUser(host *types.Host, username string) (string, error) {
+	if username == "" {
+		username = host.User
+	}
+	if username == "" {
+		currentUser, err := user.Current()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current user: %w", err)
+		}
+		username = currentUser.Username
+	}
+	return username, nil
+}
+
+func hostArgs(host *types.Host) []string {
+	args := []string{}
+
+	if host.Port > 0 && host.Port != 22 {
+		args = append(args, "-p", fmt.Sprintf("%d", host.Port))
+	}
+	if host.IdentityFile != "" {
+		args = append(args, "-i", host.IdentityFile)
+	}
+	for _, option := range host.SSHOptions {
+		args = append(args, "-o", option)
+	}
+
+	return args
+}
+
+type containerTransport struct {
+	binary string
+}
+
+func (t containerTransport) Interactive(host *types.Host, username string) (*exec.Cmd, error) {
+	args := append(t.execArgs(host, username, "-it"), host.Hostname, "sh")
+	return exec.Command(t.binary, args...), nil
+}
+
+func (t containerTransport) Command(ctx context.Context, host *types.Host, username, command string) (*exec.Cmd, error) {
+	args := append(t.execArgs(host, username), host.Hostname, "sh", "-c", command)
+	return exec.CommandContext(ctx, t.binary, args...), nil
+}
+
+func (t containerTransport) execArgs(host *types.Host, username string, flags ...string) []string {
+	args := append([]string{"exec"}, flags...)
+
+	if username == "" {
+		username = host.User
+	}
+	if username != "" {
+		args = append(args, "-u", username)
+	}
+
+	return args
+}
+
+type moshTransport struct{}
+
+func (moshTransport) Interactive(host *types.Host, username string) (*exec.Cmd, error) {
+	target, err := sshTarget(host, username)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{}
+	if sshArgs := hostArgs(host); len(sshArgs) > 0 {
+		quoted := []string{"ssh"}
+		for _, arg := range sshArgs {
+			quoted = append(quoted, shellQuote(arg))
+		}
+		args = append(args, "--ssh="+strings.Join(quoted, " "))
+	}
+
+	return exec.Command("mosh", append(args, target)...), nil
+}
+
+func (moshTransport) Command(ctx context.Context, host *types.Host, username, command string) (*exec.Cmd, error) {
+	return sshTransport{}.Command(ctx, host, username, command)
+}
+
+type etTransport struct{}
+
+func (etTransport) Interactive(host *types.Host, username string) (*exec.Cmd, error) {
+	target, err := sshTarget(host, username)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{}
+	if host.Port > 0 && host.Port != 22 {
+		args = append(args, "--ssh-option", fmt.Sprintf("Port=%d", host.Port))
+	}
+	if host.IdentityFile != "" {
+		args = append(args, "--ssh-option", "IdentityFile="+host.IdentityFile)
+	}
+	for _, option := range host.SSHOptions {
+		args = append(args, "--ssh-option", option)
+	}
+
+	return exec.Command("et", append(args, target)...), nil
+}
+
+func (etTransport) Command(ctx context.Context, host *types.Host, username, command string) (*exec.Cmd, error) {
+	return sshTransport{}.Command(ctx, host, username, command)
+}
+
+type telnetTransport struct{}
+
+func (telnetTransport) Interactive(host *types.Host, username string) (*exec.Cmd, error) {
+	if strings.HasPrefix(host.Hostname, "-") {
+		return nil, fmt.Errorf("refusing to run telnet for %s: hostname %q would be read as an option", host.Name, host.Hostname)
+	}
+
+	args := []string{}
+
+	if username == "" {
+		username = host.User
+	}
+	if username != "" {
+		args = append(args, "-l", username)
+	}
+
+	args = append(args, host.Hostname)
+	if host.Port > 0 {
+		args = append(args, fmt.Sprintf("%d", host.Port))
+	}
+
+	return exec.Command("telnet", args...), nil
+}
+
+func (telnetTransport) Command(ctx context.Context, host *types.Host, username, command string) (*exec.Cmd, error) {
+	return nil, fmt.Errorf("the telnet transport cannot run commands on %s", host.Name)
+}
+
+type templateTransport struct {
+	name        string
+	interactive []templateArg
+	command     []templateArg
+}
+
+type templateArg struct {
+	tmpl   *template.Template
+	quoted bool
+}
+
+type templateData struct {
+	Name     string
+	Hostname string
+	Port     int
+	User     string
+	Tags     []string
+	Command  string
+}
+
+var templateFuncs = template.FuncMap{"quote": shellQuote}
+
+func (t templateTransport) Interactive(host *types.Host, username string) (*exec.Cmd, error) {
+	args, err := t.render(t.interactive, host, username, "")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(args[0], args[1:]...), nil
+}
+
+func (t templateTransport) Command(ctx context.Context, host *types.Host, username, command string) (*exec.Cmd, error) {
+	if t.command == nil {
+		return nil, fmt.Errorf("the %s transport has no command template and cannot run commands on %s", t.name, host.Name)
+	}
+
+	args, err := t.render(t.command, host, username, command)
+	if err != nil {
+		return nil, err
+	}
+	return exec.CommandContext(ctx, args[0], args[1:]...), nil
+}
+
+func (t templateTransport) render(templateArgs []templateArg, host *types.Host, username, command string) ([]string, error) {
+	if username == "" {
+		username = host.User
+	}
+
+	data := templateData{
+		Name:     host.Name,
+		Hostname: host.Hostname,
+		Port:     host.Port,
+		User:     username,
+		Tags:     host.Tags,
+		Command:  command,
+	}
+
+	var args []string
+	for _, arg := range templateArgs {
+		var value strings.Builder
+		if err := arg.tmpl.Execute(&value, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s transport command for %s: %w", t.name, host.Name, err)
+		}
+		if value.Len() > 0 || arg.quoted {
+			args = append(args, value.String())
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("the %s transport command for %s is empty", t.name, host.Name)
+	}
+	return args, nil
+}
+
+func parseTemplateArgs(name, line string) ([]templateArg, error) {
+	words, err := splitTemplateWords(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("the template is empty")
+	}
+
+	args := make([]templateArg, len(words))
+	for i, word := range words {
+		tmpl, err := template.New(name).Funcs(templateFuncs).Parse(word.text)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = templateArg{tmpl: tmpl, quoted: word.quoted}
+	}
+	return args, nil
+}
+
+type templateWord struct {
+	text   string
+	quoted bool
+}
+
+func splitTemplateWords(line string) ([]templateWord, error) {
+	var words []templateWord
+	var current strings.Builder
+	inWord, quoted := false, false
+	var quote byte
+
+	finish := func() {
+		if inWord {
+			words = append(words, templateWord{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		inWord, quoted = false, false
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		if strings.HasPrefix(line[i:], "{{") {
+			end := strings.Index(line[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed action in %q", line)
+			}
+			current.WriteString(line[i : i+end+2])
+			inWord = true
+			i += end + 1
+			continue
+		}
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' && i+1 < len(line) && strings.IndexByte("\"\\$`", line[i+1]) >= 0 {
+				i++
+				current.WriteByte(line[i])
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord, quoted = true, true
+		case c == '\\' && i+1 < len(line):
+			i++
+			current.WriteByte(line[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			finish()
+		default:
+			current.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	finish()
+	return words, nil
+}
@@ -0,0 +1,205 @@
+package ssh
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+func newTemplateTransport(t *testing.T, interactive, command string) templateTransport {
+	t.Helper()
+
+	transport := templateTransport{name: "test"}
+	var err error
+	if transport.interactive, err = parseTemplateArgs("test", interactive); err != nil {
+		t.Fatalf("parse interactive template: %v", err)
+	}
+	if command != "" {
+		if transport.command, err = parseTemplateArgs("test", command); err != nil {
+			t.Fatalf("parse command template: %v", err)
+		}
+	}
+	return transport
+}
+
+func TestTemplateTransportArgs(t *testing.T) {
+	transport := newTemplateTransport(t,
+		`ssh -t "console host" connect {{.Hostname}} {{if .Port}}-p{{end}}`,
+		`kubectl exec {{.Name}} -- sh -c {{.Command}}`)
+
+	host := &types.Host{Name: "web", Hostname: "web.example.com", User: "admin"}
+
+	cmd, err := transport.Interactive(host, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ssh", "-t", "console host", "connect", "web.example.com"}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("interactive args = %q, want %q", cmd.Args, want)
+	}
+
+	cmd, err = transport.Command(context.Background(), host, "", "uptime; id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"kubectl", "exec", "web", "--", "sh", "-c", "uptime; id"}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("command args = %q, want %q", cmd.Args, want)
+	}
+}
+
+func TestTemplateTransportHostileHostFields(t *testing.T) {
+	hostile := []string{
+		"x; rm -rf ~",
+		"$(touch pwned)",
+		"`touch pwned`",
+		"a b\tc\nd",
+		"' || touch pwned '",
+		`" && touch pwned "`,
+		"--option=evil",
+		"{{.Command}}",
+	}
+
+	transport := newTemplateTransport(t, `connect {{.Hostname}} {{.User}}`, `run {{.Name}}@{{.Hostname}}`)
+
+	for _, value := range hostile {
+		host := &types.Host{Name: value, Hostname: value, User: value}
+
+		cmd, err := transport.Interactive(host, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"connect", value, value}
+		if !reflect.DeepEqual(cmd.Args, want) {
+			t.Errorf("interactive args for %q = %q, want %q", value, cmd.Args, want)
+		}
+
+		cmd, err = transport.Command(context.Background(), host, "", "true")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = []string{"run", value + "@" + value}
+		if !reflect.DeepEqual(cmd.Args, want) {
+			t.Errorf("command args for %q = %q, want %q", value, cmd.Args, want)
+		}
+	}
+}
+
+func TestTemplateTransportDoesNotRunShell(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "pwned")
+
+	transport := newTemplateTransport(t, `echo {{.Hostname}}`, `echo {{.Hostname}} {{.Command}}`)
+	host := &types.Host{Name: "evil", Hostname: "x; touch " + marker + " $(touch " + marker + ")"}
+
+	cmd, err := transport.Command(context.Background(), host, "", "`touch "+marker+"`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := host.Hostname + " `touch " + marker + "`\n"
+	if string(output) != want {
+		t.Errorf("output = %q, want %q", output, want)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("host fields were interpreted by a shell")
+	}
+}
+
+func TestSplitTemplateWords(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`a b  c`, []string{"a", "b", "c"}},
+		{`a "b c" 'd e'`, []string{"a", "b c", "d e"}},
+		{`a {{quote .Command}} b`, []string{"a", "{{quote .Command}}", "b"}},
+		{`--opt={{ .Name }}x`, []string{"--opt={{ .Name }}x"}},
+		{`a\ b "c\"d" 'e\f'`, []string{"a b", `c"d`, `e\f`}},
+		{`a ""`, []string{"a", ""}},
+	}
+
+	for _, test := range tests {
+		words, err := splitTemplateWords(test.line)
+		if err != nil {
+			t.Errorf("splitTemplateWords(%q): %v", test.line, err)
+			continue
+		}
+		var got []string
+		for _, word := range words {
+			got = append(got, word.text)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("splitTemplateWords(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+
+	for _, line := range []string{`a "b`, `a {{.Name`, `'`} {
+		if _, err := splitTemplateWords(line); err == nil {
+			t.Errorf("splitTemplateWords(%q) succeeded, want an error", line)
+		}
+	}
+}
+
+func TestTelnetTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     *types.Host
+		username string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "hostname only",
+			host: &types.Host{Name: "switch", Hostname: "switch.example.com"},
+			want: []string{"telnet", "switch.example.com"},
+		},
+		{
+			name: "host user and port",
+			host: &types.Host{Name: "switch", Hostname: "10.0.0.2", User: "admin", Port: 2323},
+			want: []string{"telnet", "-l", "admin", "10.0.0.2", "2323"},
+		},
+		{
+			name:     "user override",
+			host:     &types.Host{Name: "switch", Hostname: "10.0.0.2", User: "admin"},
+			username: "-n/tmp/trace",
+			want:     []string{"telnet", "-l", "-n/tmp/trace", "10.0.0.2"},
+		},
+		{
+			name:    "hostname that looks like an option",
+			host:    &types.Host{Name: "evil", Hostname: "-n/tmp/trace"},
+			wantErr: "would be read as an option",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd, err := telnetTransport{}.Interactive(test.host, test.username)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cmd.Args, test.want) {
+				t.Errorf("args = %q, want %q", cmd.Args, test.want)
+			}
+		})
+	}
+
+	if _, err := (telnetTransport{}).Command(context.Background(), &types.Host{Name: "switch", Hostname: "10.0.0.2"}, "", "uptime"); err == nil {
+		t.Error("the telnet transport ran a command")
+	}
+}
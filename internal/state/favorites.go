@@ -0,0 +1,121 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type favoriteEntry struct {
+	HostKey string `json:"host_key"`
+	Name    string `json:"name"`
+}
+
+type Favorites struct {
+	pinned map[string]favoriteEntry
+}
+
+func getFavoritesFile() string {
+	return filepath.Join(getStateDir(), "favorites.json")
+}
+
+func LoadFavorites() (*Favorites, error) {
+	favorites := &Favorites{pinned: make(map[string]favoriteEntry)}
+
+	data, err := os.ReadFile(getFavoritesFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return favorites, nil
+		}
+		return nil, fmt.Errorf("failed to read favorites file: %w", err)
+	}
+
+	var entries []favoriteEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse favorites file: %w", err)
+	}
+
+	for _, entry := range entries {
+		favorites.pinned[entry.HostKey] = entry
+	}
+
+	return favorites, nil
+}
+
+func (f *Favorites) IsPinned(host *types.Host) bool {
+	if f == nil {
+		return false
+	}
+	_, pinned := f.pinned[host.Key()]
+	return pinned
+}
+
+func (f *Favorites) Toggle(host *types.Host) (bool, error) {
+	if f == nil {
+		return false, fmt.Errorf("favorites are not loaded")
+	}
+
+	key := host.Key()
+	entry, pinned := f.pinned[key]
+	if pinned {
+		delete(f.pinned, key)
+	} else {
+		f.pinned[key] = favoriteEntry{HostKey: key, Name: host.Name}
+	}
+
+	if err := f.save(); err != nil {
+		if pinned {
+			f.pinned[key] = entry
+		} else {
+			delete(f.pinned, key)
+		}
+		return pinned, err
+	}
+	return !pinned, nil
+}
+
+func (f *Favorites) PinnedFirst(hosts []*types.Host) []*types.Host {
+	sorted := make([]*types.Host, len(hosts))
+	copy(sorted, hosts)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return f.IsPinned(sorted[i]) && !f.IsPinned(sorted[j])
+	})
+	return sorted
+}
+
+func (f *Favorites) PinnedHosts(hosts []*types.Host) []*types.Host {
+	var pinned []*types.Host
+	for _, host := range hosts {
+		if f.IsPinned(host) {
+			pinned = append(pinned, host)
+		}
+	}
+	return pinned
+}
+
+func (f *Favorites) save() error {
+	if err := os.MkdirAll(getStateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	entries := make([]favoriteEntry, 0, len(f.pinned))
+	for _, entry := range f.pinned {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].HostKey < entries[j].HostKey })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode favorites: %w", err)
+	}
+
+	if err := os.WriteFile(getFavoritesFile(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write favorites file: %w", err)
+	}
+	return nil
+}
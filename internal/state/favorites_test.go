@@ -0,0 +1,161 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+func TestFavoritesToggle(t *testing.T) {
+	dir := useTestStateDir(t)
+
+	web := &types.Host{Name: "web", Hostname: "web.example.com"}
+	db := &types.Host{Name: "db", Hostname: "db.example.com", User: "admin"}
+
+	favorites, err := LoadFavorites()
+	if err != nil {
+		t.Fatalf("loading a missing favorites file failed: %v", err)
+	}
+
+	tests := []struct {
+		host       *types.Host
+		wantPinned bool
+		wantFile   []string
+	}{
+		{web, true, []string{"web.example.com:22:"}},
+		{db, true, []string{"db.example.com:22:admin", "web.example.com:22:"}},
+		{web, false, []string{"db.example.com:22:admin"}},
+	}
+
+	for _, test := range tests {
+		pinned, err := favorites.Toggle(test.host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pinned != test.wantPinned || favorites.IsPinned(test.host) != test.wantPinned {
+			t.Errorf("Toggle(%s) = %v, want %v", test.host.Name, pinned, test.wantPinned)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, "favorites.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range test.wantFile {
+			if !strings.Contains(string(data), `"host_key": "`+key+`"`) {
+				t.Errorf("after toggling %s the favorites file is missing %s:\n%s", test.host.Name, key, data)
+			}
+		}
+		if got := strings.Count(string(data), `"host_key"`); got != len(test.wantFile) {
+			t.Errorf("after toggling %s the favorites file has %d entries, want %d", test.host.Name, got, len(test.wantFile))
+		}
+	}
+
+	reloaded, err := LoadFavorites()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.IsPinned(web) || !reloaded.IsPinned(db) {
+		t.Error("reloaded favorites do not match the saved state")
+	}
+}
+
+func TestFavoritesKeyedByHost(t *testing.T) {
+	useTestStateDir(t)
+
+	favorites, err := LoadFavorites()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := favorites.Toggle(&types.Host{Name: "web", Hostname: "web.example.com", User: "deploy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		host *types.Host
+		want bool
+	}{
+		{"same host under another name", &types.Host{Name: "web (from consul)", Hostname: "web.example.com", User: "deploy"}, true},
+		{"explicit default port", &types.Host{Name: "web", Hostname: "web.example.com", User: "deploy", Port: 22}, true},
+		{"other user", &types.Host{Name: "web", Hostname: "web.example.com", User: "root"}, false},
+		{"other port", &types.Host{Name: "web", Hostname: "web.example.com", User: "deploy", Port: 2222}, false},
+	}
+
+	for _, test := range tests {
+		if got := favorites.IsPinned(test.host); got != test.want {
+			t.Errorf("%s: IsPinned = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFavoritesOrdering(t *testing.T) {
+	useTestStateDir(t)
+
+	a := &types.Host{Name: "a", Hostname: "a.example.com"}
+	b := &types.Host{Name: "b", Hostname: "b.example.com"}
+	c := &types.Host{Name: "c", Hostname: "c.example.com"}
+	d := &types.Host{Name: "d", Hostname: "d.example.com"}
+
+	favorites, err := LoadFavorites()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []*types.Host{c, a} {
+		if _, err := favorites.Toggle(host); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hosts := []*types.Host{a, b, c, d}
+	if got, want := names(favorites.PinnedFirst(hosts)), []string{"a", "c", "b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PinnedFirst = %v, want %v", got, want)
+	}
+	if got, want := names(favorites.PinnedHosts(hosts)), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PinnedHosts = %v, want %v", got, want)
+	}
+}
+
+func TestFavoritesCorruptFile(t *testing.T) {
+	dir := useTestStateDir(t)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "favorites.json"), []byte(`[{"host_key": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	favorites, err := LoadFavorites()
+	if err == nil || !strings.Contains(err.Error(), "failed to parse favorites file") {
+		t.Fatalf("error = %v, want a parse error", err)
+	}
+
+	host := &types.Host{Name: "web", Hostname: "web.example.com"}
+	if favorites.IsPinned(host) {
+		t.Error("a nil favorites list reported a pinned host")
+	}
+	if _, err := favorites.Toggle(host); err == nil {
+		t.Error("toggling on a nil favorites list succeeded")
+	}
+}
+
+func TestFavoritesToggleRollsBackOnSaveFailure(t *testing.T) {
+	dir := useTestStateDir(t)
+	if err := os.MkdirAll(filepath.Join(dir, "favorites.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	favorites := &Favorites{pinned: make(map[string]favoriteEntry)}
+	host := &types.Host{Name: "web", Hostname: "web.example.com"}
+
+	pinned, err := favorites.Toggle(host)
+	if err == nil {
+		t.Fatal("saving over a directory succeeded")
+	}
+	if pinned || favorites.IsPinned(host) {
+		t.Error("a failed toggle left the host pinned")
+	}
+}
@@ -0,0 +1,168 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type HistoryEntry struct {
+	HostKey    string    `json:"host_key"`
+	Name       string    `json:"name"`
+	Hostname   string    `json:"hostname"`
+	User       string    `json:"user,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	ExitStatus int       `json:"exit_status"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+type History struct {
+	entries []HistoryEntry
+	last    map[string]HistoryEntry
+	scores  map[string]float64
+}
+
+func (e HistoryEntry) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+func getHistoryFile() string {
+	return filepath.Join(getStateDir(), "history.jsonl")
+}
+
+func RecordConnection(host *types.Host, user string, started time.Time, exitStatus int) error {
+	if err := os.MkdirAll(getStateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	entry := HistoryEntry{
+		HostKey:    host.Key(),
+		Name:       host.Name,
+		Hostname:   host.Hostname,
+		User:       user,
+		Timestamp:  started,
+		ExitStatus: exitStatus,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	file, err := os.OpenFile(getHistoryFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return nil
+}
+
+func LoadHistory() (*History, error) {
+	file, err := os.Open(getHistoryFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return newHistory(nil), nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return newHistory(entries), nil
+}
+
+func newHistory(entries []HistoryEntry) *History {
+	history := &History{
+		entries: entries,
+		last:    make(map[string]HistoryEntry),
+		scores:  make(map[string]float64),
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		if last, exists := history.last[entry.HostKey]; !exists || entry.Timestamp.After(last.Timestamp) {
+			history.last[entry.HostKey] = entry
+		}
+		history.scores[entry.HostKey] += frecencyWeight(now.Sub(entry.Timestamp))
+	}
+
+	return history
+}
+
+func (h *History) Last(hostKey string) (HistoryEntry, bool) {
+	if h == nil {
+		return HistoryEntry{}, false
+	}
+	entry, exists := h.last[hostKey]
+	return entry, exists
+}
+
+func (h *History) Frecency(hostKey string) float64 {
+	if h == nil {
+		return 0
+	}
+	return h.scores[hostKey]
+}
+
+func (h *History) SortByFrecency(hosts []*types.Host) []*types.Host {
+	sorted := make([]*types.Host, len(hosts))
+	copy(sorted, hosts)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return h.Frecency(sorted[i].Key()) > h.Frecency(sorted[j].Key())
+	})
+	return sorted
+}
+
+func (h *History) RecentHosts(hosts []*types.Host) []*types.Host {
+	var recent []*types.Host
+	for _, host := range h.SortByFrecency(hosts) {
+		if h.Frecency(host.Key()) == 0 {
+			break
+		}
+		recent = append(recent, host)
+	}
+	return recent
+}
+
+func frecencyWeight(age time.Duration) float64 {
+	switch {
+	case age < 4*time.Hour:
+		return 100
+	case age < 24*time.Hour:
+		return 80
+	case age < 7*24*time.Hour:
+		return 60
+	case age < 30*24*time.Hour:
+		return 40
+	case age < 90*24*time.Hour:
+		return 20
+	default:
+		return 10
+	}
+}
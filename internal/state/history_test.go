@@ -0,0 +1,230 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+func useTestStateDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("LSSH_STATE_DIR", "")
+	t.Setenv("XDG_STATE_HOME", dir)
+	return filepath.Join(dir, "lssh")
+}
+
+func TestGetStateDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		stateDir string
+		xdgState string
+		want     string
+	}{
+		{"LSSH_STATE_DIR wins", "/tmp/lssh-state", "/tmp/xdg", "/tmp/lssh-state"},
+		{"XDG_STATE_HOME", "", "/tmp/xdg", filepath.Join("/tmp/xdg", "lssh")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("LSSH_STATE_DIR", test.stateDir)
+			t.Setenv("XDG_STATE_HOME", test.xdgState)
+			if got := getStateDir(); got != test.want {
+				t.Errorf("getStateDir() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFrecencyWeight(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want float64
+	}{
+		{time.Minute, 100},
+		{5 * time.Hour, 80},
+		{3 * 24 * time.Hour, 60},
+		{14 * 24 * time.Hour, 40},
+		{60 * 24 * time.Hour, 20},
+		{365 * 24 * time.Hour, 10},
+	}
+
+	for _, test := range tests {
+		if got := frecencyWeight(test.age); got != test.want {
+			t.Errorf("frecencyWeight(%s) = %v, want %v", test.age, got, test.want)
+		}
+	}
+}
+
+func TestHistoryFrecency(t *testing.T) {
+	now := time.Now()
+	web := &types.Host{Name: "web", Hostname: "web.example.com"}
+	db := &types.Host{Name: "db", Hostname: "db.example.com", User: "admin"}
+	old := &types.Host{Name: "old", Hostname: "old.example.com", Port: 2222}
+	never := &types.Host{Name: "never", Hostname: "never.example.com"}
+
+	history := newHistory([]HistoryEntry{
+		{HostKey: web.Key(), Timestamp: now.Add(-200 * 24 * time.Hour)},
+		{HostKey: db.Key(), Timestamp: now.Add(-time.Hour)},
+		{HostKey: web.Key(), Timestamp: now.Add(-2 * 24 * time.Hour)},
+		{HostKey: web.Key(), Timestamp: now.Add(-10 * 24 * time.Hour)},
+		{HostKey: old.Key(), Timestamp: now.Add(-45 * 24 * time.Hour)},
+	})
+
+	tests := []struct {
+		host *types.Host
+		want float64
+	}{
+		{web, 10 + 60 + 40},
+		{db, 100},
+		{old, 20},
+		{never, 0},
+	}
+	for _, test := range tests {
+		if got := history.Frecency(test.host.Key()); got != test.want {
+			t.Errorf("Frecency(%s) = %v, want %v", test.host.Name, got, test.want)
+		}
+	}
+
+	last, ok := history.Last(web.Key())
+	if !ok || !last.Timestamp.Equal(now.Add(-2*24*time.Hour)) {
+		t.Errorf("Last(web) = %+v, %v, want the most recent connection", last, ok)
+	}
+
+	hosts := []*types.Host{never, old, db, web}
+	if got, want := names(history.SortByFrecency(hosts)), []string{"web", "db", "old", "never"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortByFrecency = %v, want %v", got, want)
+	}
+	if got, want := names(history.RecentHosts(hosts)), []string{"web", "db", "old"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentHosts = %v, want %v", got, want)
+	}
+	if got, want := names(hosts), []string{"never", "old", "db", "web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("input hosts were reordered to %v", got)
+	}
+}
+
+func TestNilHistory(t *testing.T) {
+	var history *History
+	if _, ok := history.Last("web:22:"); ok {
+		t.Error("Last on a nil history found an entry")
+	}
+	if got := history.Frecency("web:22:"); got != 0 {
+		t.Errorf("Frecency on a nil history = %v, want 0", got)
+	}
+}
+
+func TestRecordAndLoadHistory(t *testing.T) {
+	dir := useTestStateDir(t)
+
+	web := &types.Host{Name: "web", Hostname: "web.example.com"}
+	db := &types.Host{Name: "db", Hostname: "db.example.com", Port: 2200}
+
+	history, err := LoadHistory()
+	if err != nil {
+		t.Fatalf("loading a missing history file failed: %v", err)
+	}
+	if len(history.RecentHosts([]*types.Host{web, db})) != 0 {
+		t.Error("a missing history file produced recent hosts")
+	}
+
+	started := time.Now().Add(-2 * time.Second)
+	records := []struct {
+		host       *types.Host
+		user       string
+		exitStatus int
+	}{
+		{web, "", 0},
+		{db, "admin", 255},
+		{web, "deploy", 0},
+	}
+	for _, record := range records {
+		if err := RecordConnection(record.host, record.user, started, record.exitStatus); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != len(records) {
+		t.Fatalf("history file has %d lines, want one per connection:\n%s", len(lines), data)
+	}
+
+	history, err = LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last, ok := history.Last(db.Key())
+	if !ok {
+		t.Fatal("the db connection was not loaded")
+	}
+	if last.Name != "db" || last.Hostname != "db.example.com" || last.User != "admin" || last.ExitStatus != 255 {
+		t.Errorf("last db entry = %+v", last)
+	}
+	if last.Duration() < 2*time.Second {
+		t.Errorf("duration = %s, want at least the time since the connection started", last.Duration())
+	}
+	if got := history.Frecency(web.Key()); got != 200 {
+		t.Errorf("Frecency(web) = %v, want 200 for two recent connections", got)
+	}
+}
+
+func TestLoadHistorySkipsCorruptLines(t *testing.T) {
+	dir := useTestStateDir(t)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	web := &types.Host{Name: "web", Hostname: "web.example.com"}
+	if err := RecordConnection(web, "", time.Now(), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, "history.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString("not json\n{\"host_key\": \"web.example.com:22:\", \"timestamp\": \n\n"); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if err := RecordConnection(web, "", time.Now(), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := history.Frecency(web.Key()); got != 200 {
+		t.Errorf("Frecency(web) = %v, want 200 from the two valid lines", got)
+	}
+}
+
+func TestLoadHistoryUnreadable(t *testing.T) {
+	dir := useTestStateDir(t)
+	if err := os.MkdirAll(filepath.Join(dir, "history.jsonl"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadHistory(); err == nil {
+		t.Error("reading a directory as the history file succeeded")
+	}
+}
+
+func names(hosts []*types.Host) []string {
+	var names []string
+	for _, host := range hosts {
+		names = append(names, host.Name)
+	}
+	return names
+}
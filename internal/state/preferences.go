@@ -0,0 +1,50 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type Preferences struct {
+	SortMode string `json:"sort_mode,omitempty"`
+}
+
+func getPreferencesFile() string {
+	return filepath.Join(getStateDir(), "preferences.json")
+}
+
+func LoadPreferences() (*Preferences, error) {
+	preferences := &Preferences{}
+
+	data, err := os.ReadFile(getPreferencesFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return preferences, nil
+		}
+		return nil, fmt.Errorf("failed to read preferences file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, preferences); err != nil {
+		return nil, fmt.Errorf("failed to parse preferences file: %w", err)
+	}
+
+	return preferences, nil
+}
+
+func (p *Preferences) Save() error {
+	if err := os.MkdirAll(getStateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode preferences: %w", err)
+	}
+
+	if err := os.WriteFile(getPreferencesFile(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write preferences file: %w", err)
+	}
+	return nil
+}
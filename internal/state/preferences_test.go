@@ -0,0 +1,75 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreferencesRoundTrip(t *testing.T) {
+	useTestStateDir(t)
+
+	preferences, err := LoadPreferences()
+	if err != nil {
+		t.Fatalf("loading a missing preferences file failed: %v", err)
+	}
+	if preferences.SortMode != "" {
+		t.Errorf("SortMode = %q, want none", preferences.SortMode)
+	}
+
+	for _, sortMode := range []string{"name", "recent", ""} {
+		preferences.SortMode = sortMode
+		if err := preferences.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := LoadPreferences()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.SortMode != sortMode {
+			t.Errorf("saved %q, loaded %q", sortMode, loaded.SortMode)
+		}
+	}
+}
+
+func TestPreferencesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(dir string) error
+		want  string
+	}{
+		{
+			name: "corrupt file",
+			setup: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "preferences.json"), []byte(`{"sort_mode": `), 0644)
+			},
+			want: "failed to parse preferences file",
+		},
+		{
+			name: "unreadable file",
+			setup: func(dir string) error {
+				return os.Mkdir(filepath.Join(dir, "preferences.json"), 0755)
+			},
+			want: "failed to read preferences file",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := useTestStateDir(t)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := test.setup(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadPreferences()
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("error = %v, want %q", err, test.want)
+			}
+		})
+	}
+}
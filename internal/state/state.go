@@ -0,0 +1,23 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func getStateDir() string {
+	if stateDir := os.Getenv("LSSH_STATE_DIR"); stateDir != "" {
+		return stateDir
+	}
+
+	if xdgState := os.Getenv("XDG_STATE_HOME"); xdgState != "" {
+		return filepath.Join(xdgState, "lssh")
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+
+	return filepath.Join(homeDir, ".local", "state", "lssh")
+}
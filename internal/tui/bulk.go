@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tech-arch1tect/lssh/internal/bulk"
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+const (
+	maxBulkLines      = 5000
+	maxBulkLineLength = 4096
+	bulkPaneHeight    = 6
+)
+
+type OutputLine struct {
+	Stream string
+	Text   string
+	At     time.Time
+}
+
+type bulkRun struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	events chan tea.Msg
+	log    *bulkLog
+}
+
+type bulkOutputMsg struct {
+	run  *bulkRun
+	host *types.Host
+	line OutputLine
+}
+
+func newBulkRun(log *bulkLog) *bulkRun {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &bulkRun{
+		ctx:    ctx,
+		cancel: cancel,
+		events: make(chan tea.Msg, 256),
+		log:    log,
+	}
+}
+
+func (r *bulkRun) send(msg tea.Msg) {
+	select {
+	case r.events <- msg:
+	case <-r.ctx.Done():
+	}
+}
+
+func (r *bulkRun) wait() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-r.events:
+			return msg
+		case <-r.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (r *bulkRun) stop() {
+	r.cancel()
+}
+
+type bulkLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newBulkLog(path, header string) (*bulkLog, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.WriteString(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &bulkLog{file: file}, nil
+}
+
+func (l *bulkLog) Line(host *types.Host, line OutputLine) {
+	prefix := host.Name
+	if line.Stream == "stderr" {
+		prefix += " (stderr)"
+	}
+	l.Write(fmt.Sprintf("[%s] %s | %s\n", line.At.Format("15:04:05.000"), prefix, line.Text))
+}
+
+func (l *bulkLog) Event(event bulk.Event) {
+	timestamp := time.Now().Format("15:04:05.000")
+
+	if event.Host == nil {
+		if !event.Resume.IsZero() {
+			l.Write(fmt.Sprintf("[%s] pausing until %s before batch %d/%d\n", timestamp, event.Resume.Format("15:04:05"), event.Batch, event.Batches))
+		} else if event.Batches > 1 {
+			l.Write(fmt.Sprintf("[%s] starting batch %d/%d\n", timestamp, event.Batch, event.Batches))
+		}
+		return
+	}
+
+	status := event.Status.String()
+	if event.Status != bulk.StatusRunning && event.Status != bulk.StatusSkipped {
+		result := event.Result
+		status += fmt.Sprintf(" exit=%d", result.ExitCode)
+		if result.Signal != "" {
+			status += " signal=" + result.Signal
+		}
+		status += " duration=" + result.Duration.Round(time.Millisecond).String()
+		if result.Err != nil {
+			status += " error=" + result.Err.Error()
+		}
+	}
+	l.Write(fmt.Sprintf("[%s] %s (%s) %s\n", timestamp, event.Host.Name, event.Host.Hostname, status))
+}
+
+func (l *bulkLog) Summary(summary bulk.Summary) {
+	text := fmt.Sprintf("\n[%s] %d ok, %d failed, %d timed out, %d skipped", time.Now().Format("15:04:05.000"), summary.Succeeded, summary.Failed, summary.TimedOut, summary.Skipped)
+	if summary.Aborted {
+		text += " (aborted: failure threshold reached)"
+	}
+	l.Write(text + "\n")
+}
+
+func (l *bulkLog) Write(text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		_, _ = l.file.WriteString(text)
+	}
+}
+
+func (l *bulkLog) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+type bulkLineWriter struct {
+	run     *bulkRun
+	host    *types.Host
+	stream  string
+	pending []byte
+}
+
+func newBulkLineWriter(run *bulkRun, host *types.Host, stream string) *bulkLineWriter {
+	return &bulkLineWriter{run: run, host: host, stream: stream}
+}
+
+func (w *bulkLineWriter) Write(data []byte) (int, error) {
+	w.pending = append(w.pending, data...)
+
+	for {
+		index := bytes.IndexByte(w.pending, '\n')
+		if index < 0 {
+			break
+		}
+		w.emit(w.pending[:index])
+		w.pending = w.pending[index+1:]
+	}
+
+	for len(w.pending) >= maxBulkLineLength {
+		w.emit(w.pending[:maxBulkLineLength])
+		w.pending = w.pending[maxBulkLineLength:]
+	}
+
+	return len(data), nil
+}
+
+func (w *bulkLineWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.emit(w.pending)
+		w.pending = nil
+	}
+}
+
+func (w *bulkLineWriter) emit(data []byte) {
+	line := OutputLine{
+		Stream: w.stream,
+		Text:   strings.TrimRight(string(data), "\r"),
+		At:     time.Now(),
+	}
+
+	w.run.log.Line(w.host, line)
+	w.run.send(bulkOutputMsg{run: w.run, host: w.host, line: line})
+}
+
+var terminalEscapes = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07]*\x07|\x1b.`)
+
+func sanitizeOutputLine(text string) string {
+	if index := strings.LastIndexByte(text, '\r'); index >= 0 {
+		text = text[index+1:]
+	}
+	text = terminalEscapes.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "\t", "    ")
+
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+}
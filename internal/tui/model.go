@@ -6,14 +6,19 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/tech-arch1tect/lssh/internal/bulk"
+	"github.com/tech-arch1tect/lssh/internal/cache"
 	"github.com/tech-arch1tect/lssh/internal/config"
+	"github.com/tech-arch1tect/lssh/internal/probe"
 	"github.com/tech-arch1tect/lssh/internal/provider"
 	"github.com/tech-arch1tect/lssh/internal/ssh"
+	"github.com/tech-arch1tect/lssh/internal/state"
 	"github.com/tech-arch1tect/lssh/pkg/types"
 )
 
@@ -31,6 +36,8 @@ var (
 				Foreground(lipgloss.Color("170")).
 				Bold(true)
 
+	inactiveItemStyle = itemStyle.Foreground(lipgloss.Color("241"))
+
 	helpStyle = lipgloss.NewStyle().PaddingLeft(4).Foreground(lipgloss.Color("241"))
 
 	detailsPanelStyle = lipgloss.NewStyle().
@@ -51,6 +58,11 @@ var (
 
 	detailsValueStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("252"))
+
+	offlineStyle = lipgloss.NewStyle().
+			PaddingLeft(4).
+			Foreground(lipgloss.Color("214")).
+			Bold(true)
 )
 
 type ViewMode int
@@ -60,6 +72,9 @@ const (
 	GroupView
 	HostView
 	BulkCommandView
+	DiffView
+	RecentView
+	FavoritesView
 )
 
 type Model struct {
@@ -95,25 +110,115 @@ type Model struct {
 	selectedHosts     []*types.Host
 	bulkResults       map[string]*BulkCommandResult
 	bulkOutputFile    string
+	bulkRun           *bulkRun
+	bulkFocus         int
+	bulkExpanded      bool
+	bulkDialogMode    bool
+	bulkDialogField   int
+	bulkDialogValues  []string
+	bulkDialogErr     error
+	bulkOptions       bulk.Options
+	bulkBatch         int
+	bulkBatches       int
+	bulkResume        time.Time
+	bulkSummary       *bulk.Summary
+	bulkStream        string
+	bulkConfirmStop   bool
+	offline           bool
+	cachedAt          map[string]time.Time
+	diffs             []*cache.InventoryDiff
+	history           *state.History
+	recentHosts       []*types.Host
+	favorites         *state.Favorites
+	favoriteHosts     []*types.Host
+	preferences       *state.Preferences
+	sortMode          SortMode
+	hostGroups        map[string][]string
+	hostProviders     map[string]string
+	groupProviders    map[*types.Group]string
+	tableLayout       bool
+	prober            *probe.Prober
+	executor          ssh.Executor
+	hostScans         map[string]*hostScan
+	knownHosts        map[string]bool
+	warnings          []string
+}
+
+type hostScan struct {
+	result  *ssh.ScanResult
+	err     error
+	running bool
+}
+
+type offlineProvider interface {
+	IsOffline() bool
+	CachedAt() (time.Time, bool)
+}
+
+type diffProvider interface {
+	LastDiff() (*cache.InventoryDiff, bool)
+}
+
+type unwrapProvider interface {
+	Unwrap() provider.Provider
+}
+
+type warningProvider interface {
+	Warnings() []string
+}
+
+type knownHostsMatcher interface {
+	IsKnown(hostname string, port int) bool
 }
 
 type BulkCommandResult struct {
-	Host   *types.Host
-	Output string
-	Error  error
-	Done   bool
+	Host      *types.Host
+	Lines     []OutputLine
+	Status    bulk.Status
+	Error     error
+	ExitCode  int
+	Signal    string
+	StartedAt time.Time
+	Duration  time.Duration
+	Done      bool
+	scroll    int
 }
 
 type dataLoadedMsg struct {
-	groups []*types.Group
-	hosts  []*types.Host
+	groups         []*types.Group
+	hosts          []*types.Host
+	offline        bool
+	cachedAt       map[string]time.Time
+	diffs          []*cache.InventoryDiff
+	history        *state.History
+	favorites      *state.Favorites
+	preferences    *state.Preferences
+	hostGroups     map[string][]string
+	hostProviders  map[string]string
+	groupProviders map[*types.Group]string
+	knownHosts     map[string]bool
+	warnings       []string
+	err            error
+}
+
+type probeTickMsg struct{}
+
+type probeFinishedMsg struct{}
+
+type hostScanFinishedMsg struct {
+	key    string
+	result *ssh.ScanResult
 	err    error
 }
 
-type bulkCommandFinishedMsg struct {
-	host   *types.Host
-	output string
-	err    error
+type bulkEventMsg struct {
+	run   *bulkRun
+	event bulk.Event
+}
+
+type bulkRunFinishedMsg struct {
+	run     *bulkRun
+	summary bulk.Summary
 }
 
 func NewModel(providers []provider.Provider, cfg *config.Config) Model {
@@ -145,6 +250,7 @@ func newModelWithError(providers []provider.Provider, cfg *config.Config, err er
 		itemsPerPage:      0,
 		filterMode:        false,
 		filterText:        "",
+		sortMode:          SortDefault,
 		usernameMode:      false,
 		usernameText:      "",
 		bulkSelectionMode: false,
@@ -153,6 +259,15 @@ func newModelWithError(providers []provider.Provider, cfg *config.Config, err er
 		selectedHosts:     make([]*types.Host, 0),
 		bulkResults:       make(map[string]*BulkCommandResult),
 		bulkOutputFile:    "",
+		hostScans:         make(map[string]*hostScan),
+	}
+
+	if cfg != nil {
+		m.sortMode = parseSortMode(cfg.GetSortMode())
+
+		if cfg.IsProbeEnabled() {
+			m.prober = probe.NewProber(cfg.GetProbeTimeout(), cfg.GetProbeCacheTTL(), cfg.GetProbeConcurrency(), cfg.Probe.Banner)
+		}
 	}
 
 	if err != nil {
@@ -164,14 +279,60 @@ func newModelWithError(providers []provider.Provider, cfg *config.Config, err er
 }
 
 func (m Model) Init() tea.Cmd {
+	if m.prober != nil {
+		return tea.Batch(m.loadData(), probeTick())
+	}
 	return m.loadData()
 }
 
+func probeTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return probeTickMsg{}
+	})
+}
+
+func (m Model) probeVisibleHosts(force bool) tea.Cmd {
+	if m.prober == nil || m.loading || !m.isHostListView() {
+		return nil
+	}
+
+	pageHosts, ok := m.getCurrentPageItems().([]*types.Host)
+	if !ok {
+		return nil
+	}
+
+	var hosts []*types.Host
+	for _, host := range pageHosts {
+		if ssh.UsesSSH(host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	claimed := m.prober.Claim(hosts, force)
+	if len(claimed) == 0 {
+		return nil
+	}
+
+	prober := m.prober
+	return tea.Cmd(func() tea.Msg {
+		prober.Probe(context.Background(), claimed)
+		return probeFinishedMsg{}
+	})
+}
+
 func (m Model) loadData() tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		var allGroups []*types.Group
 		var allHosts []*types.Host
 		excludedHostKeys := make(map[string]bool)
+		offline := false
+		cachedAt := make(map[string]time.Time)
+		var diffs []*cache.InventoryDiff
+		hostGroups := make(map[string][]string)
+		hostProviders := make(map[string]string)
+		groupProviders := make(map[*types.Group]string)
+		var matchers []knownHostsMatcher
+		var warnings []string
 
 		for _, p := range m.providers {
 			groups, err := p.GetGroups(context.Background())
@@ -179,6 +340,33 @@ func (m Model) loadData() tea.Cmd {
 				return dataLoadedMsg{err: fmt.Errorf("failed to load data from %s: %w", p.Name(), err)}
 			}
 
+			if wp, ok := p.(warningProvider); ok {
+				for _, warning := range wp.Warnings() {
+					warnings = append(warnings, p.Name()+": "+warning)
+				}
+			}
+
+			if op, ok := p.(offlineProvider); ok && op.IsOffline() {
+				offline = true
+				if timestamp, ok := op.CachedAt(); ok {
+					cachedAt[p.Name()] = timestamp
+				}
+			}
+
+			base := p
+			if up, ok := p.(unwrapProvider); ok {
+				base = up.Unwrap()
+			}
+			if matcher, ok := base.(knownHostsMatcher); ok {
+				matchers = append(matchers, matcher)
+			}
+
+			if dp, ok := p.(diffProvider); ok {
+				if diff, ok := dp.LastDiff(); ok {
+					diffs = append(diffs, diff)
+				}
+			}
+
 			for _, group := range groups {
 				if m.config != nil && m.config.IsGroupExcluded(group.Name, config.HardExclude) {
 					for _, host := range group.AllHosts() {
@@ -187,12 +375,25 @@ func (m Model) loadData() tea.Cmd {
 				}
 			}
 
+			if m.config != nil {
+				m.config.ApplyGroupTransports(groups)
+			}
+
 			filteredGroups := m.filterGroups(groups)
 			allGroups = append(allGroups, filteredGroups...)
 
 			for _, group := range filteredGroups {
+				groupProviders[group] = p.Name()
 				groupHosts := m.filterHosts(group.AllHosts())
 				allHosts = append(allHosts, groupHosts...)
+
+				for _, host := range groupHosts {
+					key := m.hostKey(host)
+					if _, exists := hostProviders[key]; !exists {
+						hostProviders[key] = p.Name()
+					}
+					hostGroups[key] = appendUnique(hostGroups[key], group.Name)
+				}
 			}
 		}
 
@@ -204,7 +405,50 @@ func (m Model) loadData() tea.Cmd {
 		}
 
 		deduplicatedHosts := m.deduplicateHosts(finalHosts)
-		return dataLoadedMsg{groups: allGroups, hosts: deduplicatedHosts}
+
+		var knownHosts map[string]bool
+		if len(matchers) > 0 {
+			knownHosts = make(map[string]bool)
+			for _, host := range deduplicatedHosts {
+				for _, matcher := range matchers {
+					if matcher.IsKnown(host.Hostname, host.Port) {
+						knownHosts[m.hostKey(host)] = true
+						break
+					}
+				}
+			}
+		}
+
+		history, err := state.LoadHistory()
+		if err != nil {
+			return dataLoadedMsg{err: fmt.Errorf("failed to load connection history: %w", err)}
+		}
+
+		favorites, err := state.LoadFavorites()
+		if err != nil {
+			return dataLoadedMsg{err: fmt.Errorf("failed to load favorites: %w", err)}
+		}
+
+		preferences, err := state.LoadPreferences()
+		if err != nil {
+			return dataLoadedMsg{err: fmt.Errorf("failed to load preferences: %w", err)}
+		}
+
+		return dataLoadedMsg{
+			groups:         allGroups,
+			hosts:          deduplicatedHosts,
+			offline:        offline,
+			cachedAt:       cachedAt,
+			diffs:          diffs,
+			history:        history,
+			favorites:      favorites,
+			preferences:    preferences,
+			hostGroups:     hostGroups,
+			hostProviders:  hostProviders,
+			groupProviders: groupProviders,
+			knownHosts:     knownHosts,
+			warnings:       warnings,
+		}
 	})
 }
 
@@ -240,6 +484,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleBulkCommandInput(msg)
 		}
 
+		if m.bulkDialogMode {
+			return m.handleBulkDialogInput(msg)
+		}
+
+		if m.viewMode == BulkCommandView {
+			return m.handleBulkViewInput(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.quitting = true
@@ -267,7 +519,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.nextPage()
 
 		case "enter", " ":
-			if m.bulkSelectionMode && m.viewMode != GroupView {
+			if m.viewMode == DiffView {
+				return m, nil
+			} else if m.bulkSelectionMode && m.isHostListView() {
 				return m.toggleHostSelection()
 			} else if m.viewMode == GroupView {
 				return m.enterGroup()
@@ -288,14 +542,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "u":
-			if m.viewMode != GroupView {
+			if m.isHostListView() {
 				m.usernameMode = true
 				m.usernameText = ""
 				return m, nil
 			}
 
 		case "s":
-			if m.viewMode != GroupView && m.viewMode != BulkCommandView {
+			if m.isHostListView() {
 				m.bulkSelectionMode = !m.bulkSelectionMode
 				if !m.bulkSelectionMode {
 					m.selectedHosts = make([]*types.Host, 0)
@@ -303,6 +557,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+		case "f":
+			if m.isHostListView() {
+				return m.toggleFavorite()
+			}
+
+		case "t":
+			if m.isHostListView() {
+				return m.toggleTableLayout()
+			}
+
+		case "i":
+			if m.isHostListView() {
+				return m, m.scanCurrentHost()
+			}
+
+		case "r":
+			if m.prober != nil {
+				return m, m.probeVisibleHosts(true)
+			}
+
+		case "o":
+			if m.isHostListView() || m.viewMode == GroupView {
+				return m.cycleSortMode()
+			}
+
+		case "w":
+			if m.viewMode == DiffView {
+				return m.switchView()
+			}
+			if !m.bulkSelectionMode && m.viewMode != BulkCommandView {
+				return m.showDiffView()
+			}
+
 		case "c":
 			if m.bulkSelectionMode && len(m.selectedHosts) > 0 {
 				m.bulkCommandMode = true
@@ -322,23 +609,82 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		m.groups = msg.groups
 		m.hosts = msg.hosts
+		m.offline = msg.offline
+		m.cachedAt = msg.cachedAt
+		m.diffs = msg.diffs
+		m.history = msg.history
+		m.favorites = msg.favorites
+		m.preferences = msg.preferences
+		m.hostGroups = msg.hostGroups
+		m.hostProviders = msg.hostProviders
+		m.groupProviders = msg.groupProviders
+		m.knownHosts = msg.knownHosts
+		m.warnings = msg.warnings
+		if m.preferences != nil && m.preferences.SortMode != "" && (m.config == nil || !m.config.IsSortModeSet()) {
+			m.sortMode = parseSortMode(m.preferences.SortMode)
+		}
 		if msg.err != nil {
 			m.err = msg.err
 		}
 		m.updateItemsPerPage()
 		m.updateFilteredData()
 
-	case bulkCommandFinishedMsg:
-		key := fmt.Sprintf("%s@%s", msg.host.Name, msg.host.Hostname)
-		if result, exists := m.bulkResults[key]; exists {
-			result.Output = msg.output
-			result.Error = msg.err
-			result.Done = true
+	case probeTickMsg:
+		return m, tea.Batch(m.probeVisibleHosts(false), probeTick())
+
+	case probeFinishedMsg:
+		return m, nil
+
+	case hostScanFinishedMsg:
+		m.hostScans[msg.key] = &hostScan{result: msg.result, err: msg.err}
+		return m, nil
+
+	case bulkOutputMsg:
+		if msg.run != m.bulkRun {
+			return m, nil
+		}
+		if result, exists := m.bulkResults[bulkResultKey(msg.host)]; exists {
+			result.Lines = append(result.Lines, msg.line)
+			if len(result.Lines) > maxBulkLines {
+				result.Lines = result.Lines[len(result.Lines)-maxBulkLines:]
+			}
+			if result.scroll > 0 {
+				result.scroll++
+			}
+		}
+		return m, m.bulkRun.wait()
 
-			if err := m.saveBulkResult(msg.host, msg.output, msg.err); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to save result to file: %v\n", err)
+	case bulkEventMsg:
+		if msg.run != m.bulkRun {
+			return m, nil
+		}
+		if msg.event.Host == nil {
+			m.bulkBatch = msg.event.Batch
+			m.bulkBatches = msg.event.Batches
+			m.bulkResume = msg.event.Resume
+		} else if result, exists := m.bulkResults[bulkResultKey(msg.event.Host)]; exists {
+			result.Status = msg.event.Status
+			result.Done = msg.event.Status.Finished()
+			if msg.event.Status == bulk.StatusRunning {
+				result.StartedAt = time.Now()
+			}
+			if result.Done {
+				result.Error = msg.event.Result.Err
+				result.ExitCode = msg.event.Result.ExitCode
+				result.Signal = msg.event.Result.Signal
+				result.Duration = msg.event.Result.Duration
 			}
 		}
+		return m, m.bulkRun.wait()
+
+	case bulkRunFinishedMsg:
+		if msg.run != m.bulkRun {
+			return m, nil
+		}
+		m.bulkSummary = &msg.summary
+		m.bulkResume = time.Time{}
+		m.bulkRun.stop()
+		return m, nil
 	}
 
 	return m, nil
@@ -346,8 +692,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) updateFilteredData() {
 	if m.filterText == "" {
-		m.filteredHosts = m.hosts
-		m.filteredGroups = m.groups
+		m.filteredHosts = m.sortHosts(m.hosts)
+		m.filteredGroups = m.sortGroups(m.groups)
+		m.recentHosts = m.history.RecentHosts(m.hosts)
+		m.favoriteHosts = m.favorites.PinnedHosts(m.filteredHosts)
 		m.ensureCursorInBounds()
 		return
 	}
@@ -377,6 +725,11 @@ func (m *Model) updateFilteredData() {
 		}
 	}
 
+	m.filteredHosts = m.sortHosts(m.filteredHosts)
+	m.filteredGroups = m.sortGroups(m.filteredGroups)
+	m.recentHosts = m.history.RecentHosts(m.filteredHosts)
+	m.favoriteHosts = m.favorites.PinnedHosts(m.filteredHosts)
+
 	m.resetCursorAndPage()
 }
 
@@ -404,6 +757,25 @@ func (m Model) getCurrentItems() interface{} {
 		return m.filteredGroups
 	case HostView:
 		return m.getFilteredGroupHosts()
+	case RecentView:
+		return m.recentHosts
+	case FavoritesView:
+		return m.favoriteHosts
+	default:
+		return nil
+	}
+}
+
+func (m Model) getViewHosts() []*types.Host {
+	switch m.viewMode {
+	case AllHostsView:
+		return m.filteredHosts
+	case HostView:
+		return m.getFilteredGroupHosts()
+	case RecentView:
+		return m.recentHosts
+	case FavoritesView:
+		return m.favoriteHosts
 	default:
 		return nil
 	}
@@ -414,19 +786,20 @@ func (m Model) getFilteredGroupHosts() []*types.Host {
 		return nil
 	}
 
+	groupHosts := m.deduplicateHosts(m.filterHosts(m.currentGroup.AllHosts()))
 	if m.filterText == "" {
-		return m.currentGroup.Hosts
+		return m.sortHosts(groupHosts)
 	}
 
 	filterLower := strings.ToLower(m.filterText)
 	var filtered []*types.Host
-	for _, host := range m.currentGroup.Hosts {
+	for _, host := range groupHosts {
 		if strings.Contains(strings.ToLower(host.Name), filterLower) ||
 			strings.Contains(strings.ToLower(host.Hostname), filterLower) {
 			filtered = append(filtered, host)
 		}
 	}
-	return filtered
+	return m.sortHosts(filtered)
 }
 
 func (m Model) getCurrentItemCount() int {
@@ -467,6 +840,12 @@ func (m Model) formatHostItems(hosts []*types.Host) []string {
 				prefix = "[ ] "
 			}
 		}
+		if m.favorites.IsPinned(host) {
+			prefix += "★ "
+		}
+		if m.prober != nil {
+			prefix += m.probeMarker(host) + " "
+		}
 
 		maxNameLen := 40
 		name := host.Name
@@ -489,10 +868,20 @@ func (m Model) getCurrentIndex() int {
 
 func (m *Model) calculateItemsPerPage() int {
 	headerHeight := 8
+	if m.offline {
+		headerHeight += 2
+	}
+	if len(m.warnings) > 0 {
+		headerHeight += len(m.warningLines()) + 1
+	}
 	helpHeight := 2
 	paginationHeight := 1
 	availableHeight := m.terminalHeight - headerHeight - helpHeight - paginationHeight
 
+	if m.isTableLayout() {
+		availableHeight--
+	}
+
 	if availableHeight < 5 {
 		availableHeight = 5
 	}
@@ -513,6 +902,10 @@ func (m Model) getPageGridDimensions() (rows, cols int) {
 }
 
 func (m Model) calculateGridColumns() int {
+	if m.isTableLayout() {
+		return 1
+	}
+
 	detailsPanelWidth := 0
 	if m.viewMode != GroupView {
 		if m.terminalWidth > 120 {
@@ -546,7 +939,7 @@ func (m Model) calculateGridColumns() int {
 
 	if len(items) == 0 {
 		switch m.viewMode {
-		case AllHostsView, HostView:
+		case AllHostsView, HostView, RecentView, FavoritesView:
 			items = []string{"sample-host-name-1234567890123456789"}
 		case GroupView:
 			items = []string{"Sample Group Name That Is Quite Long.. (99 hosts)"}
@@ -719,7 +1112,7 @@ func (m Model) handleBulkCommandInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		m.bulkCommandMode = false
 		if m.bulkCommandText != "" {
-			return m.executeBulkCommand()
+			return m.openBulkDialog()
 		}
 		return m, nil
 	case "esc":
@@ -739,17 +1132,255 @@ func (m Model) handleBulkCommandInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+func (m Model) openBulkDialog() (tea.Model, tea.Cmd) {
+	options := bulk.Options{Parallel: 10}
+	if m.config != nil {
+		options = m.config.GetBulkOptions()
+	}
+
+	pause := ""
+	if options.Pause > 0 {
+		pause = options.Pause.String()
+	}
+
+	timeout := "0"
+	if options.Timeout > 0 {
+		timeout = options.Timeout.String()
+	}
+
+	m.bulkDialogMode = true
+	m.bulkDialogField = 0
+	m.bulkDialogErr = nil
+	m.bulkDialogValues = []string{
+		strconv.Itoa(options.Parallel),
+		options.BatchString(),
+		pause,
+		strconv.Itoa(options.MaxFailures),
+		timeout,
+	}
+	return m, nil
+}
+
+func (m Model) handleBulkDialogInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.bulkDialogErr = nil
+
+	switch msg.String() {
+	case "enter":
+		options, err := m.parseBulkDialog()
+		if err != nil {
+			m.bulkDialogErr = err
+			return m, nil
+		}
+		m.bulkDialogMode = false
+		m.bulkOptions = options
+		return m.executeBulkCommand()
+	case "esc":
+		m.bulkDialogMode = false
+		m.bulkCommandText = ""
+		return m, nil
+	case "up", "shift+tab":
+		m.bulkDialogField = (m.bulkDialogField + len(m.bulkDialogValues) - 1) % len(m.bulkDialogValues)
+		return m, nil
+	case "down", "tab":
+		m.bulkDialogField = (m.bulkDialogField + 1) % len(m.bulkDialogValues)
+		return m, nil
+	case "backspace":
+		value := m.bulkDialogValues[m.bulkDialogField]
+		if len(value) > 0 {
+			m.bulkDialogValues[m.bulkDialogField] = value[:len(value)-1]
+		}
+		return m, nil
+	default:
+		if len(msg.String()) == 1 {
+			m.bulkDialogValues[m.bulkDialogField] += msg.String()
+		}
+		return m, nil
+	}
+}
+
+func (m Model) parseBulkDialog() (bulk.Options, error) {
+	var options bulk.Options
+	var err error
+
+	if value := strings.TrimSpace(m.bulkDialogValues[0]); value != "" {
+		options.Parallel, err = strconv.Atoi(value)
+		if err != nil || options.Parallel < 0 {
+			return options, fmt.Errorf("parallel must be a number (0 for unlimited)")
+		}
+	}
+
+	options.BatchSize, options.BatchPercent, err = bulk.ParseBatch(m.bulkDialogValues[1])
+	if err != nil {
+		return options, err
+	}
+
+	if value := strings.TrimSpace(m.bulkDialogValues[2]); value != "" && value != "0" {
+		options.Pause, err = time.ParseDuration(value)
+		if err != nil || options.Pause < 0 {
+			return options, fmt.Errorf("invalid pause %q (expected a duration such as 30s)", value)
+		}
+	}
+
+	if value := strings.TrimSpace(m.bulkDialogValues[3]); value != "" {
+		options.MaxFailures, err = strconv.Atoi(value)
+		if err != nil || options.MaxFailures < 0 {
+			return options, fmt.Errorf("max failures must be a number (0 to never abort)")
+		}
+	}
+
+	if value := strings.TrimSpace(m.bulkDialogValues[4]); value != "" && value != "0" {
+		options.Timeout, err = time.ParseDuration(value)
+		if err != nil || options.Timeout < 0 {
+			return options, fmt.Errorf("invalid timeout %q (expected a duration such as 5m, or 0 for none)", value)
+		}
+	}
+
+	return options, nil
+}
+
+func (m Model) renderBulkDialog() string {
+	labels := []string{"Parallel", "Batch size", "Pause", "Max failures", "Timeout"}
+	hints := []string{"0 = unlimited", "host count or percentage, empty = one batch", "between batches", "0 = never abort", "per host, 0 = none"}
+
+	s := fmt.Sprintf("Run %q on %d hosts\n", m.bulkCommandText, len(m.selectedHosts))
+	for i, label := range labels {
+		value := m.bulkDialogValues[i]
+		line := fmt.Sprintf("%-13s %-8s", label+":", value)
+		if i == m.bulkDialogField {
+			s += selectedItemStyle.Render("> "+fmt.Sprintf("%-13s %-8s", label+":", value+"_")) + " " + helpStyle.PaddingLeft(0).Render(hints[i]) + "\n"
+		} else {
+			s += itemStyle.Render(line) + " " + helpStyle.PaddingLeft(0).Render(hints[i]) + "\n"
+		}
+	}
+
+	options, err := m.parseBulkDialog()
+	if m.bulkDialogErr != nil {
+		s += lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true).Render("Cannot run: "+m.bulkDialogErr.Error()) + "\n"
+	} else if err != nil {
+		s += lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(err.Error()) + "\n"
+	} else {
+		s += "Plan: " + options.Describe(len(m.selectedHosts)) + "\n"
+	}
+	s += helpStyle.Render("↑↓/Tab: select field, Enter: run, Esc: cancel") + "\n\n"
+	return s
+}
+
+func (m Model) handleBulkViewInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var focused *BulkCommandResult
+	if m.bulkFocus < len(m.selectedHosts) {
+		focused = m.bulkResults[bulkResultKey(m.selectedHosts[m.bulkFocus])]
+	}
+
+	if m.bulkConfirmStop {
+		m.bulkConfirmStop = false
+		if msg.String() == "y" || msg.String() == "Y" {
+			return m.switchView()
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.quitting = true
+		return m, tea.Quit
+	case "tab":
+		if m.bulkRun != nil && m.bulkSummary == nil {
+			m.bulkConfirmStop = true
+			return m, nil
+		}
+		return m.switchView()
+	case "left", "h", "shift+tab":
+		if m.bulkFocus > 0 {
+			m.bulkFocus--
+		}
+	case "right", "l":
+		if m.bulkFocus < len(m.selectedHosts)-1 {
+			m.bulkFocus++
+		}
+	case "enter":
+		m.bulkExpanded = !m.bulkExpanded
+	case "o":
+		switch m.bulkStream {
+		case "":
+			m.bulkStream = "stdout"
+		case "stdout":
+			m.bulkStream = "stderr"
+		default:
+			m.bulkStream = ""
+		}
+		for _, result := range m.bulkResults {
+			result.scroll = 0
+		}
+	case "up", "k":
+		m.scrollBulkPane(focused, 1)
+	case "down", "j":
+		m.scrollBulkPane(focused, -1)
+	case "pgup", "ctrl+u":
+		m.scrollBulkPane(focused, m.bulkPaneHeight())
+	case "pgdown", "ctrl+d":
+		m.scrollBulkPane(focused, -m.bulkPaneHeight())
+	case "home", "g":
+		if focused != nil {
+			focused.scroll = len(m.bulkLines(focused))
+			m.scrollBulkPane(focused, 0)
+		}
+	case "end", "G":
+		if focused != nil {
+			focused.scroll = 0
+		}
+	}
+	return m, nil
+}
+
+func (m Model) scrollBulkPane(result *BulkCommandResult, lines int) {
+	if result == nil {
+		return
+	}
+
+	maxScroll := len(m.bulkLines(result)) - m.bulkPaneHeight()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+
+	result.scroll += lines
+	if result.scroll > maxScroll {
+		result.scroll = maxScroll
+	}
+	if result.scroll < 0 {
+		result.scroll = 0
+	}
+}
+
+func (m Model) bulkLines(result *BulkCommandResult) []OutputLine {
+	if m.bulkStream == "" {
+		return result.Lines
+	}
+
+	var lines []OutputLine
+	for _, line := range result.Lines {
+		if line.Stream == m.bulkStream {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func (m Model) bulkPaneHeight() int {
+	if !m.bulkExpanded {
+		return bulkPaneHeight
+	}
+
+	height := m.terminalHeight - 14
+	if height < bulkPaneHeight {
+		height = bulkPaneHeight
+	}
+	return height
+}
+
 func (m Model) toggleHostSelection() (tea.Model, tea.Cmd) {
 	pageIndex := m.getCurrentIndex()
 	globalIndex := m.currentPage*m.itemsPerPage + pageIndex
-	var hosts []*types.Host
-
-	switch m.viewMode {
-	case AllHostsView:
-		hosts = m.filteredHosts
-	case HostView:
-		hosts = m.getFilteredGroupHosts()
-	}
+	hosts := m.getViewHosts()
 
 	if len(hosts) > 0 && globalIndex < len(hosts) {
 		selectedHost := hosts[globalIndex]
@@ -861,14 +1492,7 @@ func (m Model) enterGroup() (tea.Model, tea.Cmd) {
 func (m Model) selectHost() (tea.Model, tea.Cmd) {
 	pageIndex := m.getCurrentIndex()
 	globalIndex := m.currentPage*m.itemsPerPage + pageIndex
-	var hosts []*types.Host
-
-	switch m.viewMode {
-	case AllHostsView:
-		hosts = m.filteredHosts
-	case HostView:
-		hosts = m.getFilteredGroupHosts()
-	}
+	hosts := m.getViewHosts()
 
 	if len(hosts) > 0 && globalIndex < len(hosts) {
 		m.choice = hosts[globalIndex]
@@ -883,14 +1507,7 @@ func (m Model) selectHost() (tea.Model, tea.Cmd) {
 func (m Model) selectHostWithUsername() (tea.Model, tea.Cmd) {
 	pageIndex := m.getCurrentIndex()
 	globalIndex := m.currentPage*m.itemsPerPage + pageIndex
-	var hosts []*types.Host
-
-	switch m.viewMode {
-	case AllHostsView:
-		hosts = m.filteredHosts
-	case HostView:
-		hosts = m.getFilteredGroupHosts()
-	}
+	hosts := m.getViewHosts()
 
 	if len(hosts) > 0 && globalIndex < len(hosts) {
 		m.choice = hosts[globalIndex]
@@ -910,6 +1527,14 @@ func (m Model) switchView() (tea.Model, tea.Cmd) {
 		m.breadcrumb = []string{"All Groups"}
 		m.currentGroup = nil
 	case GroupView:
+		m.viewMode = RecentView
+		m.breadcrumb = []string{"Recent"}
+		m.currentGroup = nil
+	case RecentView:
+		m.viewMode = FavoritesView
+		m.breadcrumb = []string{"Favorites"}
+		m.currentGroup = nil
+	case FavoritesView:
 		m.viewMode = AllHostsView
 		m.breadcrumb = []string{"All Hosts"}
 		m.currentGroup = nil
@@ -917,6 +1542,10 @@ func (m Model) switchView() (tea.Model, tea.Cmd) {
 		m.viewMode = AllHostsView
 		m.breadcrumb = []string{"All Hosts"}
 		m.currentGroup = nil
+	case DiffView:
+		m.viewMode = AllHostsView
+		m.breadcrumb = []string{"All Hosts"}
+		m.currentGroup = nil
 	case BulkCommandView:
 		m.viewMode = AllHostsView
 		m.breadcrumb = []string{"All Hosts"}
@@ -925,11 +1554,95 @@ func (m Model) switchView() (tea.Model, tea.Cmd) {
 		m.selectedHosts = make([]*types.Host, 0)
 		m.bulkResults = make(map[string]*BulkCommandResult)
 		m.bulkOutputFile = ""
+		if m.bulkRun != nil {
+			m.bulkRun.stop()
+			m.bulkRun = nil
+		}
 	}
 
 	return m, nil
 }
 
+func (m Model) isHostListView() bool {
+	return m.viewMode == AllHostsView || m.viewMode == HostView || m.viewMode == RecentView || m.viewMode == FavoritesView
+}
+
+func (m Model) toggleFavorite() (tea.Model, tea.Cmd) {
+	host := m.getCurrentHost()
+	if host == nil {
+		return m, nil
+	}
+
+	if _, err := m.favorites.Toggle(host); err != nil {
+		m.err = fmt.Errorf("failed to update favorites: %w", err)
+		return m, nil
+	}
+
+	m.updateFilteredData()
+	m.focusHost(host)
+	return m, nil
+}
+
+func (m Model) toggleTableLayout() (tea.Model, tea.Cmd) {
+	host := m.getCurrentHost()
+	m.tableLayout = !m.tableLayout
+	m.updateItemsPerPage()
+	if host != nil {
+		m.focusHost(host)
+	}
+	return m, nil
+}
+
+func (m Model) cycleSortMode() (tea.Model, tea.Cmd) {
+	host := m.getCurrentHost()
+	m.sortMode = m.sortMode.next()
+
+	if m.preferences == nil {
+		m.preferences = &state.Preferences{}
+	}
+	m.preferences.SortMode = m.sortMode.String()
+	if err := m.preferences.Save(); err != nil {
+		m.err = fmt.Errorf("failed to save sort mode: %w", err)
+	}
+
+	m.updateFilteredData()
+	if host != nil {
+		m.focusHost(host)
+	} else {
+		m.resetCursorAndPage()
+	}
+	return m, nil
+}
+
+func (m *Model) focusHost(host *types.Host) {
+	for i, h := range m.getViewHosts() {
+		if h != host {
+			continue
+		}
+
+		if m.itemsPerPage > 0 {
+			m.currentPage = i / m.itemsPerPage
+			i = i % m.itemsPerPage
+		}
+		_, cols := m.getPageGridDimensions()
+		if cols <= 0 {
+			cols = 1
+		}
+		m.cursorRow = i / cols
+		m.cursorCol = i % cols
+		break
+	}
+	m.ensureCursorInBounds()
+}
+
+func (m Model) showDiffView() (tea.Model, tea.Cmd) {
+	m.resetCursorAndPage()
+	m.viewMode = DiffView
+	m.breadcrumb = []string{"What Changed"}
+	m.currentGroup = nil
+	return m, nil
+}
+
 func (m Model) executeBulkCommand() (tea.Model, tea.Cmd) {
 	if len(m.selectedHosts) == 0 {
 		return m, nil
@@ -955,42 +1668,80 @@ func (m Model) executeBulkCommand() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.executor == nil {
+		executorKind := "external"
+		if m.config != nil {
+			executorKind = m.config.GetExecutor()
+		}
+		m.executor, err = ssh.NewExecutor(executorKind)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+	}
+
 	m.bulkOutputFile = filepath.Join(lsshDir, filename)
 
-	err = m.initializeBulkOutputFile()
+	log, err := newBulkLog(m.bulkOutputFile, m.bulkLogHeader())
 	if err != nil {
 		m.err = fmt.Errorf("failed to create output file: %w", err)
 		return m, nil
 	}
 
+	m.bulkRun = newBulkRun(log)
+	m.bulkFocus = 0
+	m.bulkExpanded = false
+	m.bulkBatch = 0
+	m.bulkBatches = 0
+	m.bulkResume = time.Time{}
+	m.bulkSummary = nil
+	m.bulkConfirmStop = false
 	m.bulkResults = make(map[string]*BulkCommandResult)
 	for _, host := range m.selectedHosts {
-		key := fmt.Sprintf("%s@%s", host.Name, host.Hostname)
-		m.bulkResults[key] = &BulkCommandResult{
+		m.bulkResults[bulkResultKey(host)] = &BulkCommandResult{
 			Host: host,
 			Done: false,
 		}
 	}
 
-	commands := make([]tea.Cmd, len(m.selectedHosts))
-	for i, host := range m.selectedHosts {
-		commands[i] = m.executeBulkCommandOnHost(host, m.bulkCommandText)
-	}
-
-	return m, tea.Batch(commands...)
+	return m, tea.Batch(m.bulkRun.wait(), m.runBulkCommand(m.bulkCommandText))
 }
 
-func (m Model) executeBulkCommandOnHost(host *types.Host, command string) tea.Cmd {
+func (m Model) runBulkCommand(command string) tea.Cmd {
+	executor := m.executor
+	run := m.bulkRun
+	hosts := append([]*types.Host{}, m.selectedHosts...)
+	options := m.bulkOptions
 	return tea.Cmd(func() tea.Msg {
-		output, err := ssh.ExecuteCommand(context.Background(), host, command)
-		return bulkCommandFinishedMsg{
-			host:   host,
-			output: output,
-			err:    err,
+		defer run.log.Close()
+		run.log.Write("Plan: " + options.Describe(len(hosts)) + "\n\n")
+
+		summary := bulk.Execute(run.ctx, hosts, options, func(ctx context.Context, host *types.Host) bulk.Result {
+			stdout := newBulkLineWriter(run, host, "stdout")
+			stderr := newBulkLineWriter(run, host, "stderr")
+
+			result := bulk.Run(ctx, executor, host, "", command, options.Timeout, stdout, stderr)
+			stdout.Flush()
+			stderr.Flush()
+			return result
+		}, func(event bulk.Event) {
+			run.log.Event(event)
+			run.send(bulkEventMsg{run: run, event: event})
+		})
+
+		run.log.Summary(summary)
+		if run.ctx.Err() != nil {
+			run.log.Write("Stopped from the TUI: running commands were killed and the remaining hosts skipped\n")
 		}
+		run.send(bulkRunFinishedMsg{run: run, summary: summary})
+		return nil
 	})
 }
 
+func bulkResultKey(host *types.Host) string {
+	return fmt.Sprintf("%s@%s", host.Name, host.Hostname)
+}
+
 func (m Model) isHostSelected(host *types.Host) bool {
 	for _, selectedHost := range m.selectedHosts {
 		if selectedHost.Name == host.Name && selectedHost.Hostname == host.Hostname {
@@ -1000,51 +1751,14 @@ func (m Model) isHostSelected(host *types.Host) bool {
 	return false
 }
 
-func (m Model) initializeBulkOutputFile() error {
-	file, err := os.Create(m.bulkOutputFile)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
+func (m Model) bulkLogHeader() string {
 	header := "LSSH Bulk Command Execution Log\n"
 	header += "================================\n"
 	header += "Timestamp: " + time.Now().Format("2006-01-02 15:04:05") + "\n"
 	header += "Command: " + m.bulkCommandText + "\n"
 	header += fmt.Sprintf("Hosts: %d\n", len(m.selectedHosts))
 	header += "--------------------------------\n\n"
-
-	_, err = file.WriteString(header)
-	return err
-}
-
-func (m Model) saveBulkResult(host *types.Host, output string, execErr error) error {
-	if m.bulkOutputFile == "" {
-		return nil
-	}
-
-	file, err := os.OpenFile(m.bulkOutputFile, os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	timestamp := time.Now().Format("15:04:05")
-	hostHeader := fmt.Sprintf("[%s] %s (%s)\n", timestamp, host.Name, host.Hostname)
-	result := hostHeader
-
-	if execErr != nil {
-		result += fmt.Sprintf("ERROR: %v\n", execErr)
-	}
-
-	if output != "" {
-		result += fmt.Sprintf("OUTPUT:\n%s\n", output)
-	}
-
-	result += "---\n\n"
-
-	_, err = file.WriteString(result)
-	return err
+	return header
 }
 
 func (m Model) backToGroups() (tea.Model, tea.Cmd) {
@@ -1101,6 +1815,17 @@ func (m Model) View() string {
 	}
 	s += helpStyle.Render(breadcrumbStr) + "\n\n"
 
+	if m.offline {
+		s += offlineStyle.Render(m.getOfflineStatus()) + "\n\n"
+	}
+
+	if len(m.warnings) > 0 {
+		for _, line := range m.warningLines() {
+			s += offlineStyle.Render(line) + "\n"
+		}
+		s += "\n"
+	}
+
 	if m.filterMode {
 		s += "Filter: " + m.filterText + "_\n\n"
 	} else if m.filterText != "" {
@@ -1111,6 +1836,8 @@ func (m Model) View() string {
 		s += "Enter username: " + m.usernameText + "_\n\n"
 	} else if m.bulkCommandMode {
 		s += "Enter command: " + m.bulkCommandText + "_\n\n"
+	} else if m.bulkDialogMode {
+		s += m.renderBulkDialog()
 	} else if m.bulkSelectionMode {
 		s += fmt.Sprintf("Bulk Selection Mode - %d hosts selected (Space: toggle, c: command)\n\n", len(m.selectedHosts))
 	}
@@ -1134,15 +1861,16 @@ func (m Model) View() string {
 			return m.renderGridView(s, nil, groups)
 		}
 		return m.renderGridView(s, nil, m.filteredGroups)
-	case HostView:
+	case HostView, RecentView, FavoritesView:
 		pageItems := m.getCurrentPageItems()
 		if hosts, ok := pageItems.([]*types.Host); ok {
 			return m.renderGridView(s, hosts, nil)
 		}
-		hosts := m.getFilteredGroupHosts()
-		return m.renderGridView(s, hosts, nil)
+		return m.renderGridView(s, m.getViewHosts(), nil)
 	case BulkCommandView:
 		return m.renderBulkCommandView(s)
+	case DiffView:
+		return m.renderDiffView(s)
 	default:
 		return s + "Unknown view mode"
 	}
@@ -1152,11 +1880,15 @@ func (m Model) renderGridView(header string, hosts []*types.Host, groups []*type
 	s := header
 
 	var items []string
+	var inactive []bool
 	var itemCount int
 
 	if hosts != nil {
 		itemCount = len(hosts)
 		items = m.formatHostItems(hosts)
+		for _, host := range hosts {
+			inactive = append(inactive, host.Inactive)
+		}
 	} else if groups != nil {
 		itemCount = len(groups)
 		for _, group := range groups {
@@ -1172,6 +1904,16 @@ func (m Model) renderGridView(header string, hosts []*types.Host, groups []*type
 		}
 	}
 
+	if m.isTableLayout() && hosts != nil {
+		if itemCount == 0 {
+			s += "No items available.\n"
+		} else {
+			s += m.renderTable(hosts)
+		}
+		s += "\n" + helpStyle.Render(m.getHelpText())
+		return s
+	}
+
 	currentHost := m.getCurrentHost()
 	detailsPanelWidth := m.getDetailsPanelWidth(currentHost)
 	availableWidth := m.terminalWidth - detailsPanelWidth - 6
@@ -1180,7 +1922,7 @@ func (m Model) renderGridView(header string, hosts []*types.Host, groups []*type
 	if itemCount == 0 {
 		gridContent = "No items available."
 	} else {
-		gridContent = strings.TrimRight(m.renderGrid(items, itemCount, availableWidth), "\n")
+		gridContent = strings.TrimRight(m.renderGrid(items, inactive, itemCount, availableWidth), "\n")
 	}
 
 	if currentHost == nil {
@@ -1194,7 +1936,7 @@ func (m Model) renderGridView(header string, hosts []*types.Host, groups []*type
 	return s
 }
 
-func (m Model) renderGrid(items []string, itemCount, availableWidth int) string {
+func (m Model) renderGrid(items []string, inactive []bool, itemCount, availableWidth int) string {
 
 	gridCols := m.calculateGridColumns()
 
@@ -1228,6 +1970,8 @@ func (m Model) renderGrid(items []string, itemCount, availableWidth int) string
 			var styledText string
 			if isSelected {
 				styledText = selectedItemStyle.Render(displayText)
+			} else if index < len(inactive) && inactive[index] {
+				styledText = inactiveItemStyle.Render(displayText)
 			} else {
 				styledText = itemStyle.Render(displayText)
 			}
@@ -1314,7 +2058,11 @@ func (m Model) calculateColumnWidths(items []string, rows, cols, maxWidth int) [
 
 func (m Model) getHelpText() string {
 	if m.viewMode == BulkCommandView {
-		return "Tab: back to hosts, q: quit"
+		return "←→/hl: select host, ↑↓/jk/PgUp/PgDn: scroll, g/G: top/follow, Enter: expand, o: stdout/stderr, Tab: back to hosts, q: quit"
+	}
+
+	if m.viewMode == DiffView {
+		return "Tab/w: back to hosts, q: quit"
 	}
 
 	baseHelp := "↑↓←→/hjkl: navigate"
@@ -1328,15 +2076,18 @@ func (m Model) getHelpText() string {
 		baseHelp += ", Enter: select"
 	}
 
-	if m.viewMode != GroupView && m.viewMode != BulkCommandView {
-		baseHelp += ", u: custom user, s: bulk mode"
+	if m.isHostListView() {
+		baseHelp += ", u: custom user, f: pin, i: inspect keys, t: table, s: bulk mode"
+		if m.prober != nil {
+			baseHelp += ", r: re-probe"
+		}
 	}
 
 	if m.viewMode == HostView && len(m.breadcrumb) > 1 {
 		baseHelp += ", Backspace: back"
 	}
 
-	baseHelp += ", Tab: switch view, /: filter"
+	baseHelp += fmt.Sprintf(", Tab: switch view, o: sort (%s), w: what changed, /: filter", m.sortMode)
 
 	if m.getTotalPages() > 1 {
 		baseHelp += ", n/p: next/prev page"
@@ -1350,6 +2101,160 @@ func (m Model) getHelpText() string {
 	return baseHelp
 }
 
+func (m Model) getOfflineStatus() string {
+	status := "OFFLINE - showing cached data"
+
+	var ages []string
+	for _, p := range m.providers {
+		timestamp, ok := m.cachedAt[p.Name()]
+		if !ok {
+			continue
+		}
+		age := time.Since(timestamp).Round(time.Minute)
+		ages = append(ages, fmt.Sprintf("%s: %v old (%s)", p.Name(), age, timestamp.Format("2006-01-02 15:04")))
+	}
+
+	if len(ages) > 0 {
+		status += " | " + strings.Join(ages, ", ")
+	}
+
+	return status
+}
+
+func (m Model) warningLines() []string {
+	const maxWarnings = 3
+
+	var lines []string
+	for i, warning := range m.warnings {
+		if i == maxWarnings {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(m.warnings)-maxWarnings))
+			break
+		}
+		lines = append(lines, "⚠ "+warning)
+	}
+	return lines
+}
+
+func (m Model) scanCurrentHost() tea.Cmd {
+	host := m.getCurrentHost()
+	if host == nil || !ssh.UsesSSH(host) {
+		return nil
+	}
+
+	key := m.hostKey(host)
+	if scan, exists := m.hostScans[key]; exists && scan.running {
+		return nil
+	}
+	m.hostScans[key] = &hostScan{running: true}
+
+	return tea.Cmd(func() tea.Msg {
+		result, err := ssh.ScanHost(context.Background(), host, 5*time.Second)
+		return hostScanFinishedMsg{key: key, result: result, err: err}
+	})
+}
+
+func (m Model) renderHostScan(host *types.Host) string {
+	scan, exists := m.hostScans[m.hostKey(host)]
+	if !exists {
+		return ""
+	}
+
+	content := "\n" + detailsLabelStyle.Render("SSH Server:") + "\n"
+	if scan.running {
+		return content + detailsValueStyle.Render("Scanning...") + "\n"
+	}
+	if scan.err != nil {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+		return content + errorStyle.Render(truncateText(scan.err.Error(), 40)) + "\n"
+	}
+
+	if scan.result.Banner != "" {
+		content += detailsValueStyle.Render(truncateText(scan.result.Banner, 40)) + "\n"
+	}
+
+	fingerprintWidth := m.getDetailsPanelWidth(host) - 8
+	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	for _, key := range scan.result.Keys {
+		content += detailsLabelStyle.Render(strings.TrimPrefix(key.Type, "ssh-")+":") + "\n"
+		for fingerprint := key.Fingerprint; fingerprint != ""; {
+			line := fingerprint
+			if len(line) > fingerprintWidth {
+				line = line[:fingerprintWidth]
+			}
+			fingerprint = fingerprint[len(line):]
+			content += "  " + detailsValueStyle.Render(line) + "\n"
+		}
+
+		status := key.Known.String()
+		if key.Known == ssh.KnownHostMismatch || key.Known == ssh.KnownHostRevoked {
+			content += "  " + warningStyle.Render(status) + "\n"
+		} else {
+			content += "  " + detailsValueStyle.Render(status) + "\n"
+		}
+	}
+	if len(scan.result.Keys) == 0 {
+		content += detailsValueStyle.Render("No host keys received") + "\n"
+	}
+
+	return content
+}
+
+func (m Model) probeMarker(host *types.Host) string {
+	result, ok := m.prober.Result(host)
+	if !ok {
+		return fmt.Sprintf("· %-5s", "")
+	}
+
+	switch result.Status {
+	case probe.StatusUp:
+		return fmt.Sprintf("● %-5s", formatLatency(result.Latency))
+	case probe.StatusDown:
+		return fmt.Sprintf("○ %-5s", "")
+	default:
+		return fmt.Sprintf("· %-5s", "")
+	}
+}
+
+func formatLatency(latency time.Duration) string {
+	switch {
+	case latency < time.Second:
+		return fmt.Sprintf("%dms", latency.Milliseconds())
+	case latency < 10*time.Second:
+		return fmt.Sprintf("%.1fs", latency.Seconds())
+	default:
+		return fmt.Sprintf("%ds", int(latency.Seconds()))
+	}
+}
+
+func (m Model) probeStatusText(host *types.Host) string {
+	result, ok := m.prober.Result(host)
+	if !ok {
+		return "checking..."
+	}
+
+	switch result.Status {
+	case probe.StatusUp:
+		return fmt.Sprintf("up (%v)", result.Latency.Round(time.Millisecond))
+	case probe.StatusDown:
+		if result.Err != nil {
+			return "down: " + result.Err.Error()
+		}
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+func (m Model) Close() error {
+	if m.bulkRun != nil {
+		m.bulkRun.stop()
+	}
+	if m.executor == nil {
+		return nil
+	}
+	return m.executor.Close()
+}
+
 func (m Model) Choice() *types.Host {
 	return m.choice
 }
@@ -1365,16 +2270,7 @@ func (m Model) FilterText() string {
 func (m Model) getCurrentHost() *types.Host {
 	pageIndex := m.getCurrentIndex()
 	globalIndex := m.currentPage*m.itemsPerPage + pageIndex
-	var hosts []*types.Host
-
-	switch m.viewMode {
-	case AllHostsView:
-		hosts = m.filteredHosts
-	case HostView:
-		hosts = m.getFilteredGroupHosts()
-	case GroupView:
-		return nil
-	}
+	hosts := m.getViewHosts()
 
 	if len(hosts) > 0 && globalIndex < len(hosts) {
 		return hosts[globalIndex]
@@ -1447,10 +2343,44 @@ func (m Model) renderHostDetails(host *types.Host) string {
 			username = "current"
 		}
 	}
-	content += detailsLabelStyle.Render("User: ") + detailsValueStyle.Render(username) + "\n\n"
+	content += detailsLabelStyle.Render("User: ") + detailsValueStyle.Render(username) + "\n"
+
+	if host.Inactive {
+		content += detailsLabelStyle.Render("State: ") + detailsValueStyle.Render("inactive") + "\n"
+	}
+
+	if !ssh.IsSSH(host) {
+		content += detailsLabelStyle.Render("Transport: ") + detailsValueStyle.Render(host.Transport) + "\n"
+	}
+
+	if m.knownHosts != nil && ssh.UsesSSH(host) {
+		known := "no"
+		if m.knownHosts[m.hostKey(host)] {
+			known = "yes"
+		}
+		content += detailsLabelStyle.Render("Known: ") + detailsValueStyle.Render(known) + "\n"
+	}
 
-	content += detailsLabelStyle.Render("SSH Command:") + "\n"
-	content += detailsValueStyle.Render(host.SSHCommand())
+	if m.prober != nil && ssh.UsesSSH(host) {
+		content += detailsLabelStyle.Render("Status: ") + detailsValueStyle.Render(m.probeStatusText(host)) + "\n"
+		if result, ok := m.prober.Result(host); ok && result.Banner != "" {
+			content += detailsLabelStyle.Render("Banner: ") + detailsValueStyle.Render(result.Banner) + "\n"
+		}
+	}
+
+	if entry, ok := m.history.Last(host.Key()); ok {
+		lastConnected := fmt.Sprintf("%s (exit %d, %v)", entry.Timestamp.Format("2006-01-02 15:04"), entry.ExitStatus, entry.Duration().Round(time.Second))
+		content += detailsLabelStyle.Render("Last: ") + detailsValueStyle.Render(lastConnected) + "\n"
+	}
+	content += "\n"
+
+	if ssh.IsSSH(host) {
+		content += detailsLabelStyle.Render("SSH Command:") + "\n"
+	} else {
+		content += detailsLabelStyle.Render("Command:") + "\n"
+	}
+	content += detailsValueStyle.Render(ssh.CommandLine(host))
+	content += strings.TrimRight(m.renderHostScan(host), "\n")
 
 	return detailsPanelStyle.Render(content)
 }
@@ -1472,43 +2402,274 @@ func (m Model) renderBulkCommandView(header string) string {
 			completedCount++
 		}
 	}
-	s += fmt.Sprintf("Progress: %d/%d completed\n\n", completedCount, len(m.bulkResults))
+	progress := fmt.Sprintf("Progress: %d/%d completed", completedCount, len(m.bulkResults))
+	if m.bulkStream != "" {
+		progress += ", showing " + m.bulkStream + " only"
+	}
+	if m.bulkBatches > 1 {
+		progress += fmt.Sprintf(", batch %d/%d", m.bulkBatch, m.bulkBatches)
+	}
+	if !m.bulkResume.IsZero() {
+		progress += ", next batch at " + m.bulkResume.Format("15:04:05")
+	}
+	s += progress + "\n"
+	s += helpStyle.PaddingLeft(0).Render("Plan: "+m.bulkOptions.Describe(len(m.selectedHosts))) + "\n"
+	if m.bulkSummary != nil {
+		summary := fmt.Sprintf("Finished: %d ok, %d failed, %d timed out, %d skipped", m.bulkSummary.Succeeded, m.bulkSummary.Failed, m.bulkSummary.TimedOut, m.bulkSummary.Skipped)
+		if m.bulkSummary.Aborted {
+			summary += " (aborted: failure threshold reached)"
+		}
+		s += summary + "\n"
+	}
+	if m.bulkConfirmStop {
+		s += lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true).Render("The command is still running. Stop it and leave the bulk view? (y/N)") + "\n"
+	}
+	s += "\n"
 
-	for _, host := range m.selectedHosts {
-		key := fmt.Sprintf("%s@%s", host.Name, host.Hostname)
-		result, exists := m.bulkResults[key]
+	summaryTable, summaryHeight := m.renderBulkSummary()
+	s += summaryTable + "\n"
+
+	paneHeight := m.bulkPaneHeight()
+	visible := 1
+	if !m.bulkExpanded {
+		visible = (m.terminalHeight - 14 - summaryHeight) / (paneHeight + 2)
+		if visible < 1 {
+			visible = 1
+		}
+	}
+
+	start := m.bulkFocus - visible/2
+	if start > len(m.selectedHosts)-visible {
+		start = len(m.selectedHosts) - visible
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + visible
+	if end > len(m.selectedHosts) {
+		end = len(m.selectedHosts)
+	}
+
+	if start > 0 {
+		s += helpStyle.Render(fmt.Sprintf("↑ %d more hosts", start)) + "\n"
+	}
+
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	stderrStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	outputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	lineWidth := m.terminalWidth - 2
+
+	for i := start; i < end; i++ {
+		host := m.selectedHosts[i]
+		result, exists := m.bulkResults[bulkResultKey(host)]
 
 		hostHeader := "=== " + host.Name + " ==="
-		s += lipgloss.NewStyle().Bold(true).Render(hostHeader) + "\n"
+		if i == m.bulkFocus {
+			s += selectedItemStyle.PaddingLeft(0).Render(hostHeader)
+		} else {
+			s += lipgloss.NewStyle().Bold(true).Render(hostHeader)
+		}
 
 		if !exists {
-			s += "Initializing...\n\n"
+			s += "\nInitializing...\n\n"
 			continue
 		}
 
-		if !result.Done {
-			s += "Running...\n\n"
-			continue
+		lines := m.bulkLines(result)
+		status := " " + result.Status.String()
+		if details := bulkResultDetails(result); details != "" {
+			status += " (" + details + ")"
+		}
+		bottom := len(lines) - result.scroll
+		if bottom > len(lines) {
+			bottom = len(lines)
+		}
+		top := bottom - paneHeight
+		if top < 0 {
+			top = 0
+		}
+		if len(lines) > paneHeight {
+			status += fmt.Sprintf(" lines %d-%d of %d", top+1, bottom, len(lines))
+		}
+		s += helpStyle.PaddingLeft(0).Render(status) + "\n"
+
+		if len(lines) == 0 {
+			switch result.Status {
+			case bulk.StatusPending:
+				s += "Waiting...\n"
+			case bulk.StatusRunning:
+				s += "Running...\n"
+			}
+		}
+		for _, line := range lines[top:bottom] {
+			text := truncateText(sanitizeOutputLine(line.Text), lineWidth)
+			if line.Stream == "stderr" {
+				s += stderrStyle.Render(text) + "\n"
+			} else {
+				s += outputStyle.Render(text) + "\n"
+			}
 		}
 
 		if result.Error != nil {
-			errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-			s += errorStyle.Render(fmt.Sprintf("Error: %v", result.Error)) + "\n"
+			s += errorStyle.Render(truncateText(fmt.Sprintf("Error: %v", result.Error), lineWidth)) + "\n"
+		}
+		s += "\n"
+	}
+
+	if end < len(m.selectedHosts) {
+		s += helpStyle.Render(fmt.Sprintf("↓ %d more hosts", len(m.selectedHosts)-end)) + "\n"
+	}
+
+	s += "\n" + helpStyle.Render(m.getHelpText())
+	return s
+}
+
+func (m Model) renderBulkSummary() (string, int) {
+	const maxRows = 8
+
+	nameWidth := len("HOST")
+	for _, host := range m.selectedHosts {
+		if width := lipgloss.Width(host.Name); width > nameWidth {
+			nameWidth = width
 		}
+	}
+	if nameWidth > 30 {
+		nameWidth = 30
+	}
 
-		if result.Output != "" {
-			outputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-			lines := strings.Split(result.Output, "\n")
-			for _, line := range lines {
-				if strings.TrimSpace(line) != "" {
-					s += outputStyle.Render(line) + "\n"
-				}
+	start := 0
+	end := len(m.selectedHosts)
+	if end > maxRows {
+		start = m.bulkFocus - maxRows/2
+		if start > len(m.selectedHosts)-maxRows {
+			start = len(m.selectedHosts) - maxRows
+		}
+		if start < 0 {
+			start = 0
+		}
+		end = start + maxRows
+	}
+
+	widths := []int{nameWidth, 8, 5, 7, 9}
+	row := func(values ...string) string {
+		line := ""
+		for i, value := range values {
+			line += padRight(truncateText(value, widths[i]), widths[i]) + "  "
+		}
+		return strings.TrimRight(line, " ")
+	}
+
+	s := tableHeaderStyle.Render(row("HOST", "STATUS", "EXIT", "SIGNAL", "DURATION")) + "\n"
+	height := 1
+
+	for i := start; i < end; i++ {
+		host := m.selectedHosts[i]
+		result, exists := m.bulkResults[bulkResultKey(host)]
+		if !exists {
+			continue
+		}
+
+		exitCode := ""
+		duration := ""
+		switch {
+		case result.Done && result.Status != bulk.StatusSkipped:
+			if result.ExitCode >= 0 && result.Status != bulk.StatusTimedOut {
+				exitCode = strconv.Itoa(result.ExitCode)
 			}
+			duration = formatBulkDuration(result.Duration)
+		case result.Status == bulk.StatusRunning:
+			duration = formatBulkDuration(time.Since(result.StartedAt))
+		}
+
+		line := row(host.Name, result.Status.String(), exitCode, result.Signal, duration)
+		switch {
+		case i == m.bulkFocus:
+			s += tableSelectedRowStyle.PaddingLeft(0).Render(line)
+		case result.Status == bulk.StatusFailed || result.Status == bulk.StatusTimedOut:
+			s += lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(line)
+		case result.Status == bulk.StatusSucceeded:
+			s += lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render(line)
+		default:
+			s += line
 		}
 		s += "\n"
+		height++
+	}
+
+	if hidden := len(m.selectedHosts) - (end - start); hidden > 0 {
+		s += helpStyle.PaddingLeft(0).Render(fmt.Sprintf("%d more hosts, use ←→ to scroll", hidden)) + "\n"
+		height++
 	}
 
-	s += "\n" + helpStyle.Render("Tab: back to hosts, q: quit")
+	return s, height
+}
+
+func bulkResultDetails(result *BulkCommandResult) string {
+	var details []string
+
+	switch {
+	case result.Status == bulk.StatusRunning:
+		details = append(details, formatBulkDuration(time.Since(result.StartedAt)))
+	case result.Done && result.Status != bulk.StatusSkipped:
+		if result.Signal != "" {
+			details = append(details, "signal "+result.Signal)
+		} else if result.ExitCode >= 0 && result.Status != bulk.StatusTimedOut {
+			details = append(details, fmt.Sprintf("exit %d", result.ExitCode))
+		}
+		details = append(details, formatBulkDuration(result.Duration))
+	}
+
+	return strings.Join(details, ", ")
+}
+
+func formatBulkDuration(duration time.Duration) string {
+	switch {
+	case duration < time.Second:
+		return duration.Round(time.Millisecond).String()
+	case duration < time.Minute:
+		return duration.Round(100 * time.Millisecond).String()
+	default:
+		return duration.Round(time.Second).String()
+	}
+}
+
+func (m Model) renderDiffView(header string) string {
+	s := header
+
+	if len(m.diffs) == 0 {
+		s += "No inventory changes recorded yet.\n"
+		s += helpStyle.Render("Changes are tracked when cached provider data is refreshed.") + "\n"
+		s += "\n" + helpStyle.Render(m.getHelpText())
+		return s
+	}
+
+	addedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	removedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	changedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	for _, diff := range m.diffs {
+		providerHeader := fmt.Sprintf("=== %s (%s -> %s) ===", diff.Provider,
+			diff.From.Format("2006-01-02 15:04"), diff.To.Format("2006-01-02 15:04"))
+		s += lipgloss.NewStyle().Bold(true).Render(providerHeader) + "\n"
+
+		if diff.IsEmpty() {
+			s += "No changes\n\n"
+			continue
+		}
+
+		for _, host := range diff.Added {
+			s += addedStyle.Render(fmt.Sprintf("+ %s (%s)", host.Name, host.Address())) + "\n"
+		}
+		for _, host := range diff.Removed {
+			s += removedStyle.Render(fmt.Sprintf("- %s (%s)", host.Name, host.Address())) + "\n"
+		}
+		for _, change := range diff.Changed {
+			s += changedStyle.Render(fmt.Sprintf("~ %s: %s", change.Name, strings.Join(change.Describe(), ", "))) + "\n"
+		}
+		s += "\n"
+	}
+
+	s += helpStyle.Render(m.getHelpText())
 	return s
 }
 
@@ -1567,16 +2728,15 @@ func (m Model) deduplicateHosts(hosts []*types.Host) []*types.Host {
 	return result
 }
 
-func (m Model) hostKey(host *types.Host) string {
-	port := 22
-	if host.Port > 0 {
-		port = host.Port
-	}
-
-	user := ""
-	if host.User != "" {
-		user = host.User
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
 	}
+	return append(values, value)
+}
 
-	return fmt.Sprintf("%s:%d:%s", host.Hostname, port, user)
+func (m Model) hostKey(host *types.Host) string {
+	return host.Key()
 }
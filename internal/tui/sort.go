@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+type SortMode int
+
+const (
+	SortDefault SortMode = iota
+	SortByName
+	SortByHostname
+	SortByGroup
+	SortByProvider
+	SortByRecent
+)
+
+var sortModeNames = []string{"default", "name", "hostname", "group", "provider", "recent"}
+
+func (s SortMode) String() string {
+	if int(s) < 0 || int(s) >= len(sortModeNames) {
+		return sortModeNames[SortDefault]
+	}
+	return sortModeNames[s]
+}
+
+func (s SortMode) next() SortMode {
+	return SortMode((int(s) + 1) % len(sortModeNames))
+}
+
+func parseSortMode(name string) SortMode {
+	for i, modeName := range sortModeNames {
+		if strings.EqualFold(name, modeName) {
+			return SortMode(i)
+		}
+	}
+	return SortDefault
+}
+
+func (m Model) sortHosts(hosts []*types.Host) []*types.Host {
+	sorted := make([]*types.Host, len(hosts))
+	copy(sorted, hosts)
+
+	switch m.sortMode {
+	case SortByName:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return naturalLess(sorted[i].Name, sorted[j].Name)
+		})
+	case SortByHostname:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if sorted[i].Hostname != sorted[j].Hostname {
+				return naturalLess(sorted[i].Hostname, sorted[j].Hostname)
+			}
+			return naturalLess(sorted[i].Name, sorted[j].Name)
+		})
+	case SortByGroup:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			groupI, groupJ := m.primaryGroup(sorted[i]), m.primaryGroup(sorted[j])
+			if groupI != groupJ {
+				return naturalLess(groupI, groupJ)
+			}
+			return naturalLess(sorted[i].Name, sorted[j].Name)
+		})
+	case SortByProvider:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			providerI, providerJ := m.hostProviders[sorted[i].Key()], m.hostProviders[sorted[j].Key()]
+			if providerI != providerJ {
+				return naturalLess(providerI, providerJ)
+			}
+			return naturalLess(sorted[i].Name, sorted[j].Name)
+		})
+	case SortByRecent:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return naturalLess(sorted[i].Name, sorted[j].Name)
+		})
+		sorted = m.history.SortByFrecency(sorted)
+	}
+
+	if m.favorites != nil {
+		sorted = m.favorites.PinnedFirst(sorted)
+	}
+	return sorted
+}
+
+func (m Model) sortGroups(groups []*types.Group) []*types.Group {
+	sorted := make([]*types.Group, len(groups))
+	copy(sorted, groups)
+
+	switch m.sortMode {
+	case SortDefault:
+	case SortByProvider:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			providerI, providerJ := m.groupProviders[sorted[i]], m.groupProviders[sorted[j]]
+			if providerI != providerJ {
+				return naturalLess(providerI, providerJ)
+			}
+			return naturalLess(sorted[i].Name, sorted[j].Name)
+		})
+	default:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return naturalLess(sorted[i].Name, sorted[j].Name)
+		})
+	}
+
+	return sorted
+}
+
+func (m Model) primaryGroup(host *types.Host) string {
+	groups := m.hostGroups[host.Key()]
+	if len(groups) == 0 {
+		return ""
+	}
+	return groups[0]
+}
+
+func naturalLess(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			numA, restA := splitDigits(a)
+			numB, restB := splitDigits(b)
+
+			trimmedA := strings.TrimLeft(numA, "0")
+			trimmedB := strings.TrimLeft(numB, "0")
+			if len(trimmedA) != len(trimmedB) {
+				return len(trimmedA) < len(trimmedB)
+			}
+			if trimmedA != trimmedB {
+				return trimmedA < trimmedB
+			}
+			if len(numA) != len(numB) {
+				return len(numA) < len(numB)
+			}
+
+			a, b = restA, restB
+			continue
+		}
+
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) < len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
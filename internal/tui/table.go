@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tech-arch1tect/lssh/internal/probe"
+	"github.com/tech-arch1tect/lssh/internal/ssh"
+	"github.com/tech-arch1tect/lssh/pkg/types"
+)
+
+var (
+	tableHeaderStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("170"))
+
+	tableSelectedRowStyle = selectedItemStyle.PaddingLeft(4)
+
+	tableColumnTitles = map[string]string{
+		"name":           "Name",
+		"hostname":       "Hostname",
+		"port":           "Port",
+		"user":           "User",
+		"groups":         "Group(s)",
+		"provider":       "Provider",
+		"tags":           "Tags",
+		"last_connected": "Last Connected",
+		"status":         "Status",
+		"known":          "Known",
+	}
+)
+
+const (
+	tableColumnGap      = 2
+	tableMinColumnWidth = 4
+)
+
+func (m Model) isTableLayout() bool {
+	return m.tableLayout && m.isHostListView()
+}
+
+func (m Model) getTableColumns() []string {
+	var requested []string
+	if m.config != nil {
+		requested = m.config.GetTableColumns()
+	} else {
+		requested = []string{"name", "hostname", "port", "user"}
+	}
+
+	var columns []string
+	for _, column := range requested {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := tableColumnTitles[column]; ok {
+			columns = append(columns, column)
+		}
+	}
+
+	if len(columns) == 0 {
+		columns = []string{"name"}
+	}
+	return columns
+}
+
+func (m Model) tableCellValue(host *types.Host, column string) string {
+	switch column {
+	case "name":
+		return m.formatHostItems([]*types.Host{host})[0]
+	case "hostname":
+		return host.Hostname
+	case "port":
+		if host.Port > 0 {
+			return fmt.Sprintf("%d", host.Port)
+		}
+		return "22"
+	case "user":
+		if host.User == "" {
+			return "-"
+		}
+		return host.User
+	case "groups":
+		return strings.Join(m.hostGroups[host.Key()], ", ")
+	case "provider":
+		return m.hostProviders[host.Key()]
+	case "tags":
+		return strings.Join(host.Tags, ", ")
+	case "last_connected":
+		if entry, ok := m.history.Last(host.Key()); ok {
+			return formatAge(time.Since(entry.Timestamp))
+		}
+		return "-"
+	case "known":
+		if m.knownHosts == nil {
+			return "-"
+		}
+		if m.knownHosts[host.Key()] {
+			return "yes"
+		}
+		return "no"
+	case "status":
+		if m.prober == nil || !ssh.UsesSSH(host) {
+			return "-"
+		}
+		result, ok := m.prober.Result(host)
+		if !ok {
+			return "?"
+		}
+		if result.Status == probe.StatusUp {
+			return fmt.Sprintf("up %v", result.Latency.Round(time.Millisecond))
+		}
+		return result.Status.String()
+	default:
+		return ""
+	}
+}
+
+func (m Model) renderTable(hosts []*types.Host) string {
+	columns := m.getTableColumns()
+
+	rows := make([][]string, len(hosts))
+	for i, host := range hosts {
+		rows[i] = make([]string, len(columns))
+		for j, column := range columns {
+			rows[i][j] = m.tableCellValue(host, column)
+		}
+	}
+
+	widths := make([]int, len(columns))
+	for j, column := range columns {
+		widths[j] = lipgloss.Width(tableColumnTitles[column])
+		for _, row := range rows {
+			if w := lipgloss.Width(row[j]); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+
+	availableWidth := m.terminalWidth - 6 - 2 - tableColumnGap*(len(columns)-1)
+	shrinkColumns(widths, availableWidth)
+
+	header := "  "
+	for j, column := range columns {
+		header += padRight(truncateText(tableColumnTitles[column], widths[j]), widths[j])
+		if j < len(columns)-1 {
+			header += strings.Repeat(" ", tableColumnGap)
+		}
+	}
+
+	content := itemStyle.Render(tableHeaderStyle.Render(strings.TrimRight(header, " "))) + "\n"
+
+	for i, row := range rows {
+		line := ""
+		for j := range columns {
+			line += padRight(truncateText(row[j], widths[j]), widths[j])
+			if j < len(columns)-1 {
+				line += strings.Repeat(" ", tableColumnGap)
+			}
+		}
+		line = strings.TrimRight(line, " ")
+
+		if i == m.cursorRow {
+			content += tableSelectedRowStyle.Render("► "+line) + "\n"
+		} else if hosts[i].Inactive {
+			content += inactiveItemStyle.Render("  "+line) + "\n"
+		} else {
+			content += itemStyle.Render("  "+line) + "\n"
+		}
+	}
+
+	return content
+}
+
+func shrinkColumns(widths []int, availableWidth int) {
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+
+	for total > availableWidth {
+		widest := 0
+		for j := range widths {
+			if widths[j] > widths[widest] {
+				widest = j
+			}
+		}
+		if widths[widest] <= tableMinColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+func truncateText(text string, width int) string {
+	if lipgloss.Width(text) <= width {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+2 > width {
+		runes = runes[:len(runes)-1]
+	}
+	if width < 2 {
+		return string(runes)
+	}
+	return string(runes) + ".."
+}
+
+func padRight(text string, width int) string {
+	padding := width - lipgloss.Width(text)
+	if padding <= 0 {
+		return text
+	}
+	return text + strings.Repeat(" ", padding)
+}
+
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
@@ -4,19 +4,28 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/tech-arch1tect/lssh/internal/cache"
 	"github.com/tech-arch1tect/lssh/internal/config"
 	"github.com/tech-arch1tect/lssh/internal/provider"
 	"github.com/tech-arch1tect/lssh/internal/ssh"
+	"github.com/tech-arch1tect/lssh/internal/state"
 	"github.com/tech-arch1tect/lssh/internal/tui"
 )
 
 func main() {
 	clearCache := flag.Bool("clear-cache", false, "Clear all cached provider data")
+	offline := flag.Bool("offline", false, "Use cached provider data only and never contact providers")
 	flag.Parse()
 
+	if *offline {
+		os.Setenv("LSSH_OFFLINE", "true")
+	}
+
 	if *clearCache {
 		if err := cache.ClearCache(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error clearing cache: %v\n", err)
@@ -26,27 +35,103 @@ func main() {
 		return
 	}
 
+	if flag.Arg(0) == "diff" {
+		if err := runDiff(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "exec" {
+		if err := runExec(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func loadProviders() (*config.Config, []provider.Provider, error) {
 	cfg, err := config.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	names := make([]string, 0, len(cfg.Transports))
+	for name := range cfg.Transports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		transport := cfg.Transports[name]
+		if err := ssh.RegisterTemplateTransport(name, transport.Interactive, transport.Command); err != nil {
+			return nil, nil, fmt.Errorf("failed to configure transports: %w", err)
+		}
 	}
 
 	var providers []provider.Provider
 	for _, providerConfig := range cfg.Providers {
 		p, err := provider.NewProvider(providerConfig, cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create provider %s: %w", providerConfig.Name, err)
+			return nil, nil, fmt.Errorf("failed to create provider %s: %w", providerConfig.Name, err)
 		}
 		providers = append(providers, p)
 	}
 
+	return cfg, providers, nil
+}
+
+func runDiff() error {
+	_, providers, err := loadProviders()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range providers {
+		cp, ok := p.(*cache.CachedProvider)
+		if !ok {
+			fmt.Printf("%s: caching is disabled, inventory changes are not tracked\n", p.Name())
+			continue
+		}
+
+		diff, ok := cp.LastDiff()
+		if !ok {
+			fmt.Printf("%s: no inventory changes recorded yet\n", p.Name())
+			continue
+		}
+
+		fmt.Printf("%s: changes between %s and %s\n", diff.Provider,
+			diff.From.Format("2006-01-02 15:04"), diff.To.Format("2006-01-02 15:04"))
+		if diff.IsEmpty() {
+			fmt.Println("  no changes")
+			continue
+		}
+		for _, host := range diff.Added {
+			fmt.Printf("  + %s (%s)\n", host.Name, host.Address())
+		}
+		for _, host := range diff.Removed {
+			fmt.Printf("  - %s (%s)\n", host.Name, host.Address())
+		}
+		for _, change := range diff.Changed {
+			fmt.Printf("  ~ %s: %s\n", change.Name, strings.Join(change.Describe(), ", "))
+		}
+	}
+
+	return nil
+}
+
+func run() error {
+	cfg, providers, err := loadProviders()
+	if err != nil {
+		return err
+	}
+
 	if err := cache.CheckExpiredCaches(providers); err != nil {
 		return fmt.Errorf("failed to check expired caches: %w", err)
 	}
@@ -61,6 +146,8 @@ func run() error {
 
 	for {
 		if m, ok := finalModel.(tui.Model); ok {
+			m.Close()
+
 			if choice := m.Choice(); choice != nil {
 				customUser := m.CustomUsername()
 				if customUser != "" {
@@ -69,8 +156,17 @@ func run() error {
 					fmt.Printf("Connecting to %s (%s)...\n", choice.Name, choice.Hostname)
 				}
 				filterText := m.FilterText()
+				started := time.Now()
 				sshErr := ssh.ConnectWithUser(choice, customUser)
 
+				historyUser := customUser
+				if historyUser == "" {
+					historyUser = choice.User
+				}
+				if err := state.RecordConnection(choice, historyUser, started, ssh.ExitStatus(sshErr)); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to record connection history: %v\n", err)
+				}
+
 				if sshErr != nil {
 					model = tui.NewModelWithErrorAndFilter(providers, cfg, sshErr, filterText)
 				} else {
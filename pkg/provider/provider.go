@@ -10,3 +10,13 @@ type Provider interface {
 	Name() string
 	GetGroups(ctx context.Context) ([]*types.Group, error)
 }
+
+type VersionedProvider interface {
+	Provider
+	Version(ctx context.Context, since string) (string, error)
+}
+
+type WarningProvider interface {
+	Provider
+	Warnings() []string
+}
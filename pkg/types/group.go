@@ -1,10 +1,11 @@
 package types
 
 type Group struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description,omitempty"`
-	Hosts       []*Host  `json:"hosts"`
-	SubGroups   []*Group `json:"subgroups,omitempty"`
+	Name        string   `json:"name" yaml:"name" toml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Hosts       []*Host  `json:"hosts" yaml:"hosts" toml:"hosts"`
+	SubGroups   []*Group `json:"subgroups,omitempty" yaml:"subgroups,omitempty" toml:"subgroups,omitempty"`
+	Transport   string   `json:"transport,omitempty" yaml:"transport,omitempty" toml:"transport,omitempty"`
 }
 
 func (g *Group) AllHosts() []*Host {
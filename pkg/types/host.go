@@ -6,10 +6,15 @@ import (
 )
 
 type Host struct {
-	Name     string `json:"name"`
-	Hostname string `json:"hostname"`
-	Port     int    `json:"port,omitempty"`
-	User     string `json:"user,omitempty"`
+	Name         string   `json:"name" yaml:"name" toml:"name"`
+	Hostname     string   `json:"hostname" yaml:"hostname" toml:"hostname"`
+	Port         int      `json:"port,omitempty" yaml:"port,omitempty" toml:"port,omitempty"`
+	User         string   `json:"user,omitempty" yaml:"user,omitempty" toml:"user,omitempty"`
+	Tags         []string `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`
+	Inactive     bool     `json:"inactive,omitempty" yaml:"inactive,omitempty" toml:"inactive,omitempty"`
+	IdentityFile string   `json:"identity_file,omitempty" yaml:"identity_file,omitempty" toml:"identity_file,omitempty"`
+	SSHOptions   []string `json:"ssh_options,omitempty" yaml:"ssh_options,omitempty" toml:"ssh_options,omitempty"`
+	Transport    string   `json:"transport,omitempty" yaml:"transport,omitempty" toml:"transport,omitempty"`
 }
 
 func (h *Host) Address() string {
@@ -19,6 +24,15 @@ func (h *Host) Address() string {
 	return h.Hostname
 }
 
+func (h *Host) Key() string {
+	port := 22
+	if h.Port > 0 {
+		port = h.Port
+	}
+
+	return fmt.Sprintf("%s:%d:%s", h.Hostname, port, h.User)
+}
+
 func (h *Host) SSHCommand() string {
 	addr := h.Address()
 	username := h.User
@@ -27,8 +41,16 @@ func (h *Host) SSHCommand() string {
 			username = currentUser.Username
 		}
 	}
+	command := "ssh"
+	if h.IdentityFile != "" {
+		command += " -i " + h.IdentityFile
+	}
+	for _, option := range h.SSHOptions {
+		command += " -o " + option
+	}
+
 	if username != "" {
-		return fmt.Sprintf("ssh %s@%s", username, addr)
+		return fmt.Sprintf("%s %s@%s", command, username, addr)
 	}
-	return fmt.Sprintf("ssh %s", addr)
+	return fmt.Sprintf("%s %s", command, addr)
 }